@@ -15,6 +15,10 @@ type symbols struct{}
 
 // ListTags returns symbols in a repository from ctags.
 func (symbols) ListTags(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+	if Mocks.Symbols.ListTags != nil {
+		return Mocks.Symbols.ListTags(ctx, args)
+	}
+
 	result, err := symbolsclient.DefaultClient.Search(ctx, args)
 	if result == nil {
 		return nil, err
@@ -0,0 +1,12 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+)
+
+type MockSymbols struct {
+	ListTags func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error)
+}
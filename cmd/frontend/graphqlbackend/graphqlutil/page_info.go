@@ -2,8 +2,10 @@ package graphqlutil
 
 // PageInfo implements the GraphQL type PageInfo.
 type PageInfo struct {
-	endCursor   *string
-	hasNextPage bool
+	startCursor     *string
+	endCursor       *string
+	hasPreviousPage bool
+	hasNextPage     bool
 }
 
 // HasNextPage returns a new PageInfo with the given hasNextPage value.
@@ -17,5 +19,18 @@ func NextPageCursor(endCursor string) *PageInfo {
 	return &PageInfo{endCursor: &endCursor, hasNextPage: true}
 }
 
-func (r *PageInfo) EndCursor() *string { return r.endCursor }
-func (r *PageInfo) HasNextPage() bool  { return r.hasNextPage }
+// NewPageInfo returns a new PageInfo for a connection that can be paginated
+// both forwards and backwards.
+func NewPageInfo(startCursor, endCursor *string, hasPreviousPage, hasNextPage bool) *PageInfo {
+	return &PageInfo{
+		startCursor:     startCursor,
+		endCursor:       endCursor,
+		hasPreviousPage: hasPreviousPage,
+		hasNextPage:     hasNextPage,
+	}
+}
+
+func (r *PageInfo) StartCursor() *string  { return r.startCursor }
+func (r *PageInfo) EndCursor() *string    { return r.endCursor }
+func (r *PageInfo) HasPreviousPage() bool { return r.hasPreviousPage }
+func (r *PageInfo) HasNextPage() bool     { return r.hasNextPage }
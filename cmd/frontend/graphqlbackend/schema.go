@@ -1348,6 +1348,77 @@ type Query {
         # how many results to return per page. It must be in the range of 0-5000.
         first: Int
     ): Search
+    # Lists the symbols defined at the HEAD commit of each of the given repositories. Failures to list
+    # the symbols of a repository are reported in the connection's errors and don't prevent returning
+    # the symbols of the other repositories.
+    symbols(
+        # The names of the repositories (at most 100).
+        repositories: [String!]!
+        # Returns the first n symbols (across all repositories, which are ordered by name) from the list
+        # (at most 1,000 unless the site configuration's search.symbols.maxPageSize says otherwise).
+        first: Int
+        # Return symbols matching the query. Unless regExp is true, a query that is a qualified name
+        # (e.g., "Server.Serve" or "vector::push_back") also matches against symbol names qualified by
+        # their container names, using the container separator of each symbol's language.
+        query: String
+        # Whether the query is a regular expression (the default) or a literal string. Regular
+        # expressions use RE2 syntax and are matched against symbol names.
+        regexp: Boolean
+        # How a literal query is matched against symbol names (e.g., PREFIX for autocompletion). If
+        # set, the query is treated as a literal string, and regexp must not be true.
+        match: SymbolMatch
+        # Whether the query is matched case-sensitively. By default, it is matched case-insensitively.
+        caseSensitive: Boolean
+        # Return only symbols whose container name (see Symbol.containerName) matches this query, which
+        # is interpreted like the query argument (using regexp, match, and caseSensitive).
+        containerQuery: String
+        # Return only symbols in files whose paths match all of these regular expressions (like the
+        # file: search filter). Like the query, they are matched case-insensitively unless caseSensitive
+        # is true.
+        includePatterns: [String!]
+        # Omit symbols in files whose paths match any of these regular expressions (like the -file:
+        # search filter), matched like includePatterns.
+        excludePatterns: [String!]
+        # Omit symbols in vendored and generated files, as determined by the site configuration's
+        # search.symbols.generatedPatterns. Defaults to false.
+        excludeGenerated: Boolean
+        # Return only symbols in files of any of these languages (e.g., "go" or "typescript"), as
+        # determined by their file extensions (like the lang: search filter).
+        languages: [String!]
+        # Return only symbols of these kinds. If empty or unset, symbols of all kinds are returned.
+        kinds: [SymbolKind!]
+        # Omit symbols that name references to definitions elsewhere (such as imported package names)
+        # rather than definitions, as determined by the site configuration's
+        # search.symbols.referenceKinds. Defaults to true.
+        definitionsOnly: Boolean
+        # Omit symbols that are declarations of symbols defined elsewhere (such as C function
+        # prototypes), keeping only their definitions (see Symbol.isDefinition). Defaults to false.
+        excludeDeclarations: Boolean
+        # Omit symbols whose visibility is PRIVATE (see Symbol.visibility), keeping exported symbols
+        # and those whose visibility is unknown. Defaults to false.
+        exportedOnly: Boolean
+        # Return only top-level symbols, which are those that aren't in a container (such as a
+        # class). Their nested symbols are still available as their children (see Symbol.children),
+        # so outlines can show the top-level symbols first and expand them lazily. Defaults to false.
+        topLevelOnly: Boolean
+        # Return only symbols whose bodies contain this string (or match it as a regular expression if
+        # regexp is true), case-insensitively unless caseSensitive is true. A symbol's body is the lines
+        # from its start through the end of its definition (e.g., a function's closing brace) if ctags
+        # reports it, and otherwise only its start line. This is expensive, because the file that
+        # defines each symbol must be read, so first must be at most 100 and the symbols must be
+        # defined in at most 50 files.
+        bodyQuery: String
+        # Return only symbols whose start lines were last changed (according to git blame) after this
+        # time: an RFC 3339 date, or a revision whose author date is used. Symbols that can't be blamed
+        # (e.g., those of directories) are kept. This is expensive, because each file that defines a
+        # symbol must be blamed, so first must be at most 100 and the symbols must be defined in at
+        # most 100 files.
+        since: String
+        # Omit symbols whose files don't exist at the commit (which can happen if the symbols source's
+        # index is stale). This requires listing the directories that contain the symbols, so it is off
+        # by default.
+        validatePaths: Boolean
+    ): RepositoriesSymbolConnection!
     # All saved searches configured for the current user, merged from all configurations.
     savedSearches: [SavedSearch!]!
     # All repository groups for the current user, merged from all configurations.
@@ -2155,6 +2226,48 @@ type RepositoryComparison {
         # Return the first n file diffs from the list.
         first: Int
     ): FileDiffConnection!
+    # The symbols that were added, removed, or modified in the files changed between the base and head.
+    # Like fileDiffs, the head is compared with the merge base of the base and head. Symbols are
+    # matched by name, container name, and file, so a symbol in a renamed file is reported as removed
+    # and added. At most 500 changed files are compared.
+    symbolChanges(
+        # Return the first n symbol changes from the list (at most 1,000 unless the site configuration's
+        # search.symbols.maxPageSize says otherwise).
+        first: Int
+    ): SymbolChangeConnection!
+}
+
+# A list of symbol changes.
+type SymbolChangeConnection {
+    # A list of symbol changes, ordered by the location of the symbol in the head (or, for removed
+    # symbols, the base).
+    nodes: [SymbolChange!]!
+    # Whether the list is incomplete, because there were more changes or changed files than the limits,
+    # or because the changed files had more symbols in the base or head than the symbols limit (in
+    # which case only some of the changed files are compared).
+    limitHit: Boolean!
+    # The failures of symbols sources that occurred while computing the symbols of the base and head.
+    errors: [SymbolSourceError!]!
+}
+
+# A symbol that was added, removed, or modified between two commits.
+type SymbolChange {
+    # The kind of change.
+    kind: SymbolChangeKind!
+    # The symbol in the base, or null if it was added.
+    base: Symbol
+    # The symbol in the head, or null if it was removed.
+    head: Symbol
+}
+
+# The kinds of symbol changes.
+enum SymbolChangeKind {
+    # The symbol exists only in the head.
+    ADDED
+    # The symbol exists only in the base.
+    REMOVED
+    # The symbol exists in both, but its kind or signature changed.
+    MODIFIED
 }
 
 # A list of file diffs.
@@ -2261,23 +2374,102 @@ type RepositoryContributor {
 # It is derived from DocumentSymbol as defined in the Language Server Protocol (see
 # https://microsoft.github.io/language-server-protocol/specifications/specification-3-14/#textDocument_documentSymbol).
 type Symbol {
+    # An opaque identifier of the symbol that is stable across requests for the same symbol at the
+    # same commit (e.g., for keying lists or deep-linking to the symbol). It is not a Node ID.
+    id: ID!
     # The name of the symbol.
     name: String!
     # The name of the symbol that contains this symbol, if any. This field's value is not guaranteed to be
     # structured in such a way that callers can infer a hierarchy of symbols.
     containerName: String
+    # The package, namespace, or module that contains the symbol, if known. Unlike containerName, this is
+    # never a class or other type, so it distinguishes symbols with the same name in different packages.
+    package: String
+    # How relevant the symbol's name is to the query that the symbol list was requested with, from 0 (not
+    # similar) to 100 (an exact match), or null if there was no query. Exact, prefix, substring, and
+    # fuzzy (subsequence) matches score in decreasing order.
+    score: Int
+    # The ranges of the symbol's name that matched the query that the symbol list was requested with,
+    # so that clients can highlight them without matching the query again. For FUZZY matches, they
+    # are the matched characters; otherwise, the range is the first match of the query. Empty if there
+    # was no query.
+    nameMatchRanges: [SymbolNameRange!]!
+    # The number of other symbols with the same language, container, and name that were collapsed into
+    # this one by the deduplicateByMoniker argument (0 if it wasn't given). To list them, request the
+    # symbols without deduplicateByMoniker.
+    duplicateCount: Int!
     # The kind of the symbol.
     kind: SymbolKind!
+    # The number of the symbol's kind in the LSP SymbolKind enumeration (see
+    # https://microsoft.github.io/language-server-protocol/specification#textDocument_documentSymbol),
+    # or 0 if the kind is unknown. Unlike kind, this can represent kinds that are not (yet) in the
+    # SymbolKind enum.
+    kindInt: Int!
     # The programming language of the symbol.
     language: String!
+    # An identifier of the logical symbol that is the same in all repositories and commits where the
+    # symbol is defined (e.g., "go:Server.Serve"), for correlating definitions of the same symbol. It
+    # is derived from the language, container name, and name of the symbol, so it is best-effort:
+    # unrelated symbols with the same qualified name in the same language have the same moniker.
+    moniker: String!
     # The location where this symbol is defined.
     location: Location!
+    # The location of the symbol's definition, from precise code intelligence at the symbol's location.
+    # This can be more precise than location, whose position comes from ctags and can be off. It is
+    # the same as location if no precise code intelligence is available for the symbol's file.
+    definition: Location!
+    # The commit that the symbol is defined at (the same as location.resource.commit), so that
+    # symbols merged from multiple repositories or commits identify where they came from. This is the
+    # indexed commit if the symbol list fell back to it (see SymbolConnection.fallbackCommit).
+    commit: GitCommit!
+    # The line (zero-based) of the start of the symbol's range. This is the same as
+    # location.range.start.line, for convenience.
+    line: Int!
+    # The character (zero-based) of the start of the symbol's range on its line.
+    character: Int!
+    # The line (zero-based) of the end of the symbol's range.
+    endLine: Int!
+    # The character (zero-based) of the end of the symbol's range (exclusive).
+    endCharacter: Int!
+    # The range of the symbol's name in its file (the same as location.range), as byte offsets from the
+    # start of the file. This is for tools that operate on raw bytes instead of lines and characters.
+    # It is null if the name can't be found at the symbol's position (e.g., because ctags reported an
+    # inexact position). Computing it requires reading the file.
+    byteRange: SymbolByteRange
+    # The source lines of the symbol's range (from line to endLine) in its file, for previewing the
+    # symbol's definition (e.g., in search results). It is null if the range is not in the file.
+    # Computing it requires reading the file.
+    snippet(
+        # The number of lines before and after the symbol's range to include (default 0, at most 10).
+        contextLines: Int
+    ): String
+    # Whether the symbol is a definition, as opposed to a declaration of a symbol that is defined
+    # elsewhere (such as a C function prototype or extern variable). Symbols are assumed to be
+    # definitions unless their source reports that they are declarations.
+    isDefinition: Boolean!
+    # Whether the symbol is visible outside of the file or package it is defined in, as inferred from
+    # the naming conventions of its language (e.g., capitalized names in Go).
+    visibility: SymbolVisibility!
     # The URL to this symbol (using the input revision specifier, which may not be immutable).
     url: String!
     # The canonical URL to this symbol (using an immutable revision specifier).
     canonicalURL: String!
     # Whether or not the symbol is local to the file it's defined in.
     fileLocal: Boolean!
+    # The symbols defined in the same file whose container is this symbol (e.g., the methods of a
+    # class). This is empty if the symbol is not part of a symbol list or if the hierarchy can't be
+    # determined unambiguously, in which case clients should show a flat list of symbols.
+    children: [Symbol!]!
+    # Details about the symbol, such as the signature of a function (e.g., "(x int) error"), or null if
+    # none are known.
+    detail: String
+    # The documentation of the symbol (e.g., its doc comment), from precise code intelligence. This is
+    # null if no precise code intelligence is available for the symbol's file.
+    documentation: String
+    # The number of references to the symbol (at most 100), from precise code intelligence. This is
+    # null if no precise code intelligence is available for the symbol's file or if the references
+    # can't be determined quickly.
+    referenceCount: Int
 }
 
 # A location inside a resource (in a repository at a specific commit).
@@ -2308,6 +2500,17 @@ type Position {
     character: Int!
 }
 
+# Whether a symbol is visible outside of the file or package (or other unit of code) it is defined in.
+enum SymbolVisibility {
+    # The symbol is exported.
+    PUBLIC
+    # The symbol is only visible in its file, package, class, or other unit of code.
+    PRIVATE
+    # The symbol's visibility can't be inferred (e.g., because its language has no naming convention
+    # for it).
+    UNKNOWN
+}
+
 # All possible kinds of symbols. This set matches that of the Language Server Protocol
 # (https://microsoft.github.io/language-server-protocol/specification#workspace_symbol).
 enum SymbolKind {
@@ -2344,8 +2547,149 @@ enum SymbolKind {
 type SymbolConnection {
     # A list of symbols.
     nodes: [Symbol!]!
+    # The symbols in nodes, grouped by the file they are defined in (e.g., for a tree view). The
+    # files are ordered by path, and the symbols in each file by location, regardless of orderBy.
+    nodesByFile: [SymbolFileGroup!]!
+    # The total number of symbols in the connection, including those not on the current page. The same
+    # filters (query, kinds, etc.) apply. The count is capped at 10,000.
+    totalCount: Int!
+    # The number of symbols of each kind in the connection, including those not on the current page,
+    # ordered by decreasing count. Only kinds with at least one symbol are included. The same filters
+    # and cap as for totalCount apply.
+    kindCounts: [SymbolKindCount!]!
+    # The distinct kinds of the symbols in the connection (the kinds in kindCounts), in alphabetical
+    # order, for showing only the kind filters that apply. The same filters and cap as for totalCount
+    # apply, so kinds may be missing if countsLimitHit is true.
+    kindsPresent: [SymbolKind!]!
+    # Whether totalCount, kindCounts, and kindsPresent omit symbols because more symbols than the cap
+    # of 10,000 satisfy the arguments.
+    countsLimitHit: Boolean!
     # Pagination information.
     pageInfo: PageInfo!
+    # Whether symbols were omitted from nodes because of the first argument: either more symbols
+    # satisfy the arguments (between the after and before cursors, if given) than are in nodes, or a
+    # symbols source returned more symbols than the limit before they were filtered, so there may be
+    # more. Unlike pageInfo.hasNextPage, this does not depend on the direction of paging.
+    truncated: Boolean!
+    # The failures of symbols sources that occurred while computing the symbols. When a source
+    # fails, the symbols from the other sources (or the partial results of the failed source) are
+    # still returned, so the list of symbols may be incomplete if this is non-empty.
+    errors: [SymbolSourceError!]!
+    # Whether no symbols source could list the symbols (e.g., because none is configured, or the query
+    # is shorter than the minimum length for all of them). If true, nodes is empty because the symbols
+    # aren't available, not because no symbols match, and errors says why.
+    unavailable: Boolean!
+    # How long each symbols source that was used took to return the symbols, for debugging slow
+    # requests. If the symbols were cached, this has a single entry for the "cache" source. Only site
+    # admins may view this field.
+    sourceTimings: [SymbolSourceTiming!]!
+    # The indexed commit that the symbols were listed at instead of the requested commit (because of
+    # the useIndexedRevision argument), or null if they were listed at the requested commit. If set,
+    # the symbols' locations refer to this commit and the symbols may be stale.
+    fallbackCommit: GitCommit
+}
+
+# The symbols of a symbol list that are defined in the same file.
+type SymbolFileGroup {
+    # The path of the file, relative to the repository root.
+    path: String!
+    # The symbols defined in the file, ordered by location.
+    symbols: [Symbol!]!
+}
+
+# How long a symbols source took to return the symbols of a symbol list.
+type SymbolSourceTiming {
+    # The name of the symbols source (e.g., "indexed search", "symbols service", or "cache").
+    source: String!
+    # How long the source took, in milliseconds.
+    durationMilliseconds: Int!
+    # The number of symbols that the source returned, before any filtering.
+    resultCount: Int!
+    # The error message, if the source failed.
+    error: String
+}
+
+# A range in a file, as byte offsets from the start of the file.
+type SymbolByteRange {
+    # The byte offset of the start of the range (inclusive).
+    start: Int!
+    # The byte offset of the end of the range (exclusive).
+    end: Int!
+}
+
+# A range in a symbol's name, as byte offsets from the start of the name.
+type SymbolNameRange {
+    # The byte offset of the start of the range (inclusive).
+    start: Int!
+    # The byte offset of the end of the range (exclusive).
+    end: Int!
+}
+
+# The ways that a literal query can be matched against symbol names.
+enum SymbolMatch {
+    # The name starts with the query.
+    PREFIX
+    # The name contains the query.
+    SUBSTRING
+    # The name contains all of the characters of the query, in order (e.g., "nsr" matches
+    # "newSymbolResolver").
+    FUZZY
+    # The name contains the query as whole words: the query starts and ends at word boundaries,
+    # which are non-alphanumeric characters (e.g., "_" in snake_case) and camelCase humps (e.g.,
+    # "get" matches "getUser" and "get_user" but not "forget" or "getter"). Sites can turn off
+    # camelCase word boundaries per language with the site configuration's
+    # search.symbols.wordBoundaries.
+    WHOLE_WORD
+}
+
+# The possible orders of a list of symbols.
+enum SymbolOrderBy {
+    # By file path, then by position in the file (the natural order when browsing files).
+    LOCATION
+    # By name (case-insensitively), then by location.
+    NAME
+    # By kind, grouping types first, then functions and methods, then fields and properties, then
+    # variables and constants, then modules and namespaces, and then other kinds. Symbols of the same
+    # group are ordered by name and then by location.
+    KIND
+    # By relevance to the query (see Symbol.score), with the most relevant first, and then by location.
+    # Without a query, this is the same as LOCATION.
+    RELEVANCE
+}
+
+# The number of symbols of a kind.
+type SymbolKindCount {
+    # The kind of the symbols.
+    kind: SymbolKind!
+    # The number of symbols of the kind.
+    count: Int!
+}
+
+# A list of symbols from multiple repositories.
+type RepositoriesSymbolConnection {
+    # A list of symbols. Each symbol's location refers to the repository that it is defined in.
+    nodes: [Symbol!]!
+    # Whether more symbols exist than were returned (because of the limit on the number of symbols
+    # returned, either overall or for a single repository).
+    limitHit: Boolean!
+    # The failures to list the symbols of the repositories.
+    errors: [RepositorySymbolsError!]!
+}
+
+# A failure to list (some of) the symbols of a repository.
+type RepositorySymbolsError {
+    # The name of the repository.
+    repository: String!
+    # The error message.
+    message: String!
+}
+
+# A failure of one of the sources of symbols (such as indexed search or the symbols service).
+type SymbolSourceError {
+    # The name of the symbols source that failed (e.g., "indexed search" or "symbols service").
+    source: String!
+    # The error message.
+    message: String!
 }
 
 # A Git object ID (SHA-1 hash, 40 hexadecimal characters).
@@ -2463,8 +2807,14 @@ type PhabricatorRepo {
 
 # Pagination information. See https://facebook.github.io/relay/graphql/connections.htm#sec-undefined.PageInfo.
 type PageInfo {
+    # When paginating backwards, the cursor to continue. Only set for connections that support
+    # paginating backwards.
+    startCursor: String
     # When paginating forwards, the cursor to continue.
     endCursor: String
+    # When paginating backwards, are there more items? Always false for connections that do not support
+    # paginating backwards.
+    hasPreviousPage: Boolean!
     # When paginating forwards, are there more items?
     hasNextPage: Boolean!
 }
@@ -2540,6 +2890,15 @@ type GitCommit implements Node {
     file(path: String!): File2
     # Lists the programming languages present in the tree at this commit.
     languages: [String!]!
+    # Whether symbols are available for each of the programming languages present in the tree at this
+    # commit (in the same order as languages), or for the given languages. This does not list any
+    # symbols, so it is cheap.
+    symbolProviders(
+        # The languages to report on (e.g., "go" or "TypeScript", like the lang: search filter), for
+        # clients that already know the languages of the tree. If empty or unset, the languages are
+        # determined from the tree, which is slower.
+        languages: [String!]
+    ): [SymbolProvider!]!
     # List statistics for each language present in the repository.
     languageStatistics: [LanguageStatistics!]!
     # The log of commits consisting of this commit and its ancestors.
@@ -2557,14 +2916,156 @@ type GitCommit implements Node {
     behindAhead(revspec: String!): BehindAheadCounts!
     # Symbols defined as of this commit. (All symbols, not just symbols that were newly defined in this commit.)
     symbols(
-        # Returns the first n symbols from the list.
+        # Returns the first n symbols from the list (at most 1,000 unless the site configuration's
+        # search.symbols.maxPageSize says otherwise).
         first: Int
-        # Return symbols matching the query.
+        # Return symbols matching the query. Unless regExp is true, a query that is a qualified name
+        # (e.g., "Server.Serve" or "vector::push_back") also matches against symbol names qualified by
+        # their container names, using the container separator of each symbol's language.
         query: String
-        # A list of regular expressions, all of which must match all
-        # file paths returned in the list.
+        # Whether the query is a regular expression (the default) or a literal string. Regular
+        # expressions use RE2 syntax and are matched against symbol names.
+        regexp: Boolean
+        # How a literal query is matched against symbol names (e.g., PREFIX for autocompletion). If
+        # set, the query is treated as a literal string, and regexp must not be true.
+        match: SymbolMatch
+        # Whether the query is matched case-sensitively. By default, it is matched case-insensitively.
+        caseSensitive: Boolean
+        # Return only symbols whose container name (see Symbol.containerName) matches this query, which
+        # is interpreted like the query argument (using regexp, match, and caseSensitive).
+        containerQuery: String
+        # Return only symbols in files whose paths match all of these regular expressions (like the
+        # file: search filter). Paths are relative to the repository root, and patterns are not
+        # implicitly anchored (e.g., use "^src/api/" for the symbols under the top-level src/api directory).
+        # Like the query, they are matched case-insensitively unless caseSensitive is true.
         includePatterns: [String!]
+        # Omit symbols in files whose paths match any of these regular expressions (like the -file:
+        # search filter), matched like includePatterns.
+        excludePatterns: [String!]
+        # Return only symbols in these files (paths relative to the repository root), e.g., the files
+        # changed in a pull request. This lists the symbols of all of the files in one request, which is
+        # faster than a request per file. At most 500 paths may be given.
+        paths: [String!]
+        # Omit symbols in vendored and generated files (e.g., in vendor/ and node_modules/ directories),
+        # as determined by the site configuration's search.symbols.generatedPatterns. Defaults to false.
+        excludeGenerated: Boolean
+        # Return only symbols in files of any of these languages (e.g., "go" or "typescript"), as
+        # determined by their file extensions (like the lang: search filter).
+        languages: [String!]
+        # Return only symbols of these kinds. If empty or unset, symbols of all kinds are returned.
+        kinds: [SymbolKind!]
+        # Omit symbols that name references to definitions elsewhere (such as imported package names)
+        # rather than definitions, as determined by the site configuration's
+        # search.symbols.referenceKinds. Defaults to true.
+        definitionsOnly: Boolean
+        # Omit symbols that are declarations of symbols defined elsewhere (such as C function
+        # prototypes), keeping only their definitions (see Symbol.isDefinition). Defaults to false.
+        excludeDeclarations: Boolean
+        # Omit symbols whose visibility is PRIVATE (see Symbol.visibility), keeping exported symbols
+        # and those whose visibility is unknown. Defaults to false.
+        exportedOnly: Boolean
+        # Return only top-level symbols, which are those that aren't in a container (such as a
+        # class). Their nested symbols are still available as their children (see Symbol.children),
+        # so outlines can show the top-level symbols first and expand them lazily. Defaults to false.
+        topLevelOnly: Boolean
+        # Return only symbols whose bodies contain this string (or match it as a regular expression if
+        # regexp is true), case-insensitively unless caseSensitive is true. A symbol's body is the lines
+        # from its start through the end of its definition (e.g., a function's closing brace) if ctags
+        # reports it, and otherwise only its start line. This is expensive, because the file that
+        # defines each symbol must be read, so first must be at most 100 and the symbols must be
+        # defined in at most 50 files.
+        bodyQuery: String
+        # Return only symbols whose start lines were last changed (according to git blame) after this
+        # time: an RFC 3339 date, or a revision whose author date is used. Symbols that can't be blamed
+        # (e.g., those of directories) are kept. This is expensive, because each file that defines a
+        # symbol must be blamed, so first must be at most 100 and the symbols must be defined in at
+        # most 100 files.
+        since: String
+        # Omit symbols whose files don't exist at the commit (which can happen if the symbols source's
+        # index is stale). This requires listing the directories that contain the symbols, so it is off
+        # by default.
+        validatePaths: Boolean
+        # Collapse symbols with the same name, container, file, and position into one (the default). Set
+        # to false to return the raw list of symbols from the backend (e.g., for debugging).
+        deduplicate: Boolean
+        # Also collapse symbols with the same language, container, and name into one even if they are
+        # defined in different files (e.g., a symbol and its re-exports), keeping the definition. The
+        # number of symbols collapsed into each one is its duplicateCount. Defaults to false.
+        deduplicateByMoniker: Boolean
+        # The order of the symbols. Defaults to LOCATION.
+        orderBy: SymbolOrderBy
+        # Whether to reverse the order given by orderBy. Defaults to false.
+        descending: Boolean
+        # Skip this many symbols from the start of the list (for simple offset-based paging). This cannot be
+        # combined with the after and before cursors, and offset plus first must be at most 10,000 (or
+        # 100 if bodyQuery or since is given).
+        offset: Int
+        # If the symbols at this commit are not indexed, list the symbols at the commit that the
+        # repository's default branch is indexed at instead, which is faster (see
+        # SymbolConnection.fallbackCommit). Those symbols may be stale or unrelated to this commit, so
+        # clients should say so when this happens. Defaults to false.
+        useIndexedRevision: Boolean
+        # Return the symbols after this cursor (from a previous page's pageInfo.endCursor).
+        #
+        # Cursors hold the sort keys of a symbol (its path, position, and name, and the orderBy key),
+        # not its index in the list, so paging resumes at the same place in the order even if the list
+        # changed between requests (e.g., because the indexed revision changed). This is best-effort:
+        # if the cursor's symbol no longer exists, the page starts at the next symbol that sorts after
+        # it; symbols added before the cursor are not returned; and symbols whose position changed may
+        # be skipped or returned twice.
+        after: String
+        # Return the page of symbols immediately before this cursor (from a previous page's
+        # pageInfo.startCursor). Like after, this is best-effort if the list changed.
+        before: String
     ): SymbolConnection!
+    # Whether any symbols are defined (at the commit, or in this file or directory), for showing or
+    # hiding symbol lists. This fetches the first page of symbols with the default arguments, so a
+    # subsequent symbols request with the default arguments is served from the cache.
+    hasSymbols: Boolean!
+    # The symbol with the given name (and container name, if given) defined as of this commit, or null
+    # if there is none. If multiple symbols match, the first one in the order of their locations is
+    # returned. This is intended for linking to a symbol without listing all of the symbols.
+    symbol(
+        # The exact (case-sensitive) name of the symbol.
+        name: String!
+        # The exact (case-sensitive) name of the symbol's container. If unset, symbols in any
+        # container (or none) match. Use the empty string to match only symbols without a container.
+        containerName: String
+    ): Symbol
+    # All of the symbols defined as of this commit, a page at a time, for building external symbol
+    # indexes. Unlike symbols, it can page through every symbol (without a limit on the total), but
+    # the symbols are in an unspecified (but stable) order and are not filtered or deduplicated.
+    # Only site admins may use it.
+    allSymbols(
+        # The number of symbols on the page (default and maximum 250).
+        first: Int
+        # Return the symbols after this cursor (from a previous page's pageInfo.endCursor).
+        #
+        # Cursors hold the sort keys of a symbol (its path, position, and name, and the orderBy key),
+        # not its index in the list, so paging resumes at the same place in the order even if the list
+        # changed between requests (e.g., because the indexed revision changed). This is best-effort:
+        # if the cursor's symbol no longer exists, the page starts at the next symbol that sorts after
+        # it; symbols added before the cursor are not returned; and symbols whose position changed may
+        # be skipped or returned twice.
+        after: String
+    ): SymbolExportConnection!
+}
+
+# Whether symbols are available for a programming language.
+type SymbolProvider {
+    # The name of the programming language (as in GitCommit.languages).
+    language: String!
+    # Whether symbols are listed for files in the language. If false, symbol lists never contain
+    # symbols defined in files of the language.
+    available: Boolean!
+}
+
+# A page of all of the symbols defined as of a commit.
+type SymbolExportConnection {
+    # The symbols on this page.
+    nodes: [Symbol!]!
+    # Pagination information.
+    pageInfo: PageInfo!
 }
 
 # A set of Git behind/ahead counts for one commit relative to another.
@@ -2623,11 +3124,112 @@ interface TreeEntry {
     externalURLs: [ExternalLink!]!
     # Symbols defined in this file or directory.
     symbols(
-        # Returns the first n symbols from the list.
+        # Returns the first n symbols from the list (at most 1,000 unless the site configuration's
+        # search.symbols.maxPageSize says otherwise).
         first: Int
-        # Return symbols matching the query.
+        # Return symbols matching the query. Unless regExp is true, a query that is a qualified name
+        # (e.g., "Server.Serve" or "vector::push_back") also matches against symbol names qualified by
+        # their container names, using the container separator of each symbol's language.
         query: String
+        # Whether the query is a regular expression (the default) or a literal string. Regular
+        # expressions use RE2 syntax and are matched against symbol names.
+        regexp: Boolean
+        # How a literal query is matched against symbol names (e.g., PREFIX for autocompletion). If
+        # set, the query is treated as a literal string, and regexp must not be true.
+        match: SymbolMatch
+        # Whether the query is matched case-sensitively. By default, it is matched case-insensitively.
+        caseSensitive: Boolean
+        # Return only symbols whose container name (see Symbol.containerName) matches this query, which
+        # is interpreted like the query argument (using regexp, match, and caseSensitive).
+        containerQuery: String
+        # Return only symbols in files whose paths match all of these regular expressions (like the
+        # file: search filter). Paths are relative to the repository root, and patterns are not
+        # implicitly anchored (e.g., use "^src/api/" for the symbols under the top-level src/api directory).
+        # Like the query, they are matched case-insensitively unless caseSensitive is true.
+        includePatterns: [String!]
+        # Omit symbols in files whose paths match any of these regular expressions (like the -file:
+        # search filter), matched like includePatterns.
+        excludePatterns: [String!]
+        # Return only symbols in these files (paths relative to the repository root), e.g., the files
+        # changed in a pull request. This lists the symbols of all of the files in one request, which is
+        # faster than a request per file. At most 500 paths may be given.
+        paths: [String!]
+        # Omit symbols in vendored and generated files (e.g., in vendor/ and node_modules/ directories),
+        # as determined by the site configuration's search.symbols.generatedPatterns. Defaults to false.
+        excludeGenerated: Boolean
+        # Return only symbols in files of any of these languages (e.g., "go" or "typescript"), as
+        # determined by their file extensions (like the lang: search filter).
+        languages: [String!]
+        # Return only symbols of these kinds. If empty or unset, symbols of all kinds are returned.
+        kinds: [SymbolKind!]
+        # Omit symbols that name references to definitions elsewhere (such as imported package names)
+        # rather than definitions, as determined by the site configuration's
+        # search.symbols.referenceKinds. Defaults to true.
+        definitionsOnly: Boolean
+        # Omit symbols that are declarations of symbols defined elsewhere (such as C function
+        # prototypes), keeping only their definitions (see Symbol.isDefinition). Defaults to false.
+        excludeDeclarations: Boolean
+        # Omit symbols whose visibility is PRIVATE (see Symbol.visibility), keeping exported symbols
+        # and those whose visibility is unknown. Defaults to false.
+        exportedOnly: Boolean
+        # Return only top-level symbols, which are those that aren't in a container (such as a
+        # class). Their nested symbols are still available as their children (see Symbol.children),
+        # so outlines can show the top-level symbols first and expand them lazily. Defaults to false.
+        topLevelOnly: Boolean
+        # Return only symbols whose bodies contain this string (or match it as a regular expression if
+        # regexp is true), case-insensitively unless caseSensitive is true. A symbol's body is the lines
+        # from its start through the end of its definition (e.g., a function's closing brace) if ctags
+        # reports it, and otherwise only its start line. This is expensive, because the file that
+        # defines each symbol must be read, so first must be at most 100 and the symbols must be
+        # defined in at most 50 files.
+        bodyQuery: String
+        # Return only symbols whose start lines were last changed (according to git blame) after this
+        # time: an RFC 3339 date, or a revision whose author date is used. Symbols that can't be blamed
+        # (e.g., those of directories) are kept. This is expensive, because each file that defines a
+        # symbol must be blamed, so first must be at most 100 and the symbols must be defined in at
+        # most 100 files.
+        since: String
+        # Omit symbols whose files don't exist at the commit (which can happen if the symbols source's
+        # index is stale). This requires listing the directories that contain the symbols, so it is off
+        # by default.
+        validatePaths: Boolean
+        # Collapse symbols with the same name, container, file, and position into one (the default). Set
+        # to false to return the raw list of symbols from the backend (e.g., for debugging).
+        deduplicate: Boolean
+        # Also collapse symbols with the same language, container, and name into one even if they are
+        # defined in different files (e.g., a symbol and its re-exports), keeping the definition. The
+        # number of symbols collapsed into each one is its duplicateCount. Defaults to false.
+        deduplicateByMoniker: Boolean
+        # The order of the symbols. Defaults to LOCATION.
+        orderBy: SymbolOrderBy
+        # Whether to reverse the order given by orderBy. Defaults to false.
+        descending: Boolean
+        # Skip this many symbols from the start of the list (for simple offset-based paging). This cannot be
+        # combined with the after and before cursors, and offset plus first must be at most 10,000 (or
+        # 100 if bodyQuery or since is given).
+        offset: Int
+        # If the symbols at this commit are not indexed, list the symbols at the commit that the
+        # repository's default branch is indexed at instead, which is faster (see
+        # SymbolConnection.fallbackCommit). Those symbols may be stale or unrelated to this commit, so
+        # clients should say so when this happens. Defaults to false.
+        useIndexedRevision: Boolean
+        # Return the symbols after this cursor (from a previous page's pageInfo.endCursor).
+        #
+        # Cursors hold the sort keys of a symbol (its path, position, and name, and the orderBy key),
+        # not its index in the list, so paging resumes at the same place in the order even if the list
+        # changed between requests (e.g., because the indexed revision changed). This is best-effort:
+        # if the cursor's symbol no longer exists, the page starts at the next symbol that sorts after
+        # it; symbols added before the cursor are not returned; and symbols whose position changed may
+        # be skipped or returned twice.
+        after: String
+        # Return the page of symbols immediately before this cursor (from a previous page's
+        # pageInfo.startCursor). Like after, this is best-effort if the list changed.
+        before: String
     ): SymbolConnection!
+    # Whether any symbols are defined (at the commit, or in this file or directory), for showing or
+    # hiding symbol lists. This fetches the first page of symbols with the default arguments, so a
+    # subsequent symbols request with the default arguments is served from the cache.
+    hasSymbols: Boolean!
     # Submodule metadata if this tree points to a submodule
     submodule: Submodule
     # Whether this tree entry is a single child
@@ -2689,11 +3291,112 @@ type GitTree implements TreeEntry {
     ): [TreeEntry!]!
     # Symbols defined in this tree.
     symbols(
-        # Returns the first n symbols from the list.
+        # Returns the first n symbols from the list (at most 1,000 unless the site configuration's
+        # search.symbols.maxPageSize says otherwise).
         first: Int
-        # Return symbols matching the query.
+        # Return symbols matching the query. Unless regExp is true, a query that is a qualified name
+        # (e.g., "Server.Serve" or "vector::push_back") also matches against symbol names qualified by
+        # their container names, using the container separator of each symbol's language.
         query: String
+        # Whether the query is a regular expression (the default) or a literal string. Regular
+        # expressions use RE2 syntax and are matched against symbol names.
+        regexp: Boolean
+        # How a literal query is matched against symbol names (e.g., PREFIX for autocompletion). If
+        # set, the query is treated as a literal string, and regexp must not be true.
+        match: SymbolMatch
+        # Whether the query is matched case-sensitively. By default, it is matched case-insensitively.
+        caseSensitive: Boolean
+        # Return only symbols whose container name (see Symbol.containerName) matches this query, which
+        # is interpreted like the query argument (using regexp, match, and caseSensitive).
+        containerQuery: String
+        # Return only symbols in files whose paths match all of these regular expressions (like the
+        # file: search filter). Paths are relative to the repository root, and patterns are not
+        # implicitly anchored (e.g., use "^src/api/" for the symbols under the top-level src/api directory).
+        # Like the query, they are matched case-insensitively unless caseSensitive is true.
+        includePatterns: [String!]
+        # Omit symbols in files whose paths match any of these regular expressions (like the -file:
+        # search filter), matched like includePatterns.
+        excludePatterns: [String!]
+        # Return only symbols in these files (paths relative to the repository root), e.g., the files
+        # changed in a pull request. This lists the symbols of all of the files in one request, which is
+        # faster than a request per file. At most 500 paths may be given.
+        paths: [String!]
+        # Omit symbols in vendored and generated files (e.g., in vendor/ and node_modules/ directories),
+        # as determined by the site configuration's search.symbols.generatedPatterns. Defaults to false.
+        excludeGenerated: Boolean
+        # Return only symbols in files of any of these languages (e.g., "go" or "typescript"), as
+        # determined by their file extensions (like the lang: search filter).
+        languages: [String!]
+        # Return only symbols of these kinds. If empty or unset, symbols of all kinds are returned.
+        kinds: [SymbolKind!]
+        # Omit symbols that name references to definitions elsewhere (such as imported package names)
+        # rather than definitions, as determined by the site configuration's
+        # search.symbols.referenceKinds. Defaults to true.
+        definitionsOnly: Boolean
+        # Omit symbols that are declarations of symbols defined elsewhere (such as C function
+        # prototypes), keeping only their definitions (see Symbol.isDefinition). Defaults to false.
+        excludeDeclarations: Boolean
+        # Omit symbols whose visibility is PRIVATE (see Symbol.visibility), keeping exported symbols
+        # and those whose visibility is unknown. Defaults to false.
+        exportedOnly: Boolean
+        # Return only top-level symbols, which are those that aren't in a container (such as a
+        # class). Their nested symbols are still available as their children (see Symbol.children),
+        # so outlines can show the top-level symbols first and expand them lazily. Defaults to false.
+        topLevelOnly: Boolean
+        # Return only symbols whose bodies contain this string (or match it as a regular expression if
+        # regexp is true), case-insensitively unless caseSensitive is true. A symbol's body is the lines
+        # from its start through the end of its definition (e.g., a function's closing brace) if ctags
+        # reports it, and otherwise only its start line. This is expensive, because the file that
+        # defines each symbol must be read, so first must be at most 100 and the symbols must be
+        # defined in at most 50 files.
+        bodyQuery: String
+        # Return only symbols whose start lines were last changed (according to git blame) after this
+        # time: an RFC 3339 date, or a revision whose author date is used. Symbols that can't be blamed
+        # (e.g., those of directories) are kept. This is expensive, because each file that defines a
+        # symbol must be blamed, so first must be at most 100 and the symbols must be defined in at
+        # most 100 files.
+        since: String
+        # Omit symbols whose files don't exist at the commit (which can happen if the symbols source's
+        # index is stale). This requires listing the directories that contain the symbols, so it is off
+        # by default.
+        validatePaths: Boolean
+        # Collapse symbols with the same name, container, file, and position into one (the default). Set
+        # to false to return the raw list of symbols from the backend (e.g., for debugging).
+        deduplicate: Boolean
+        # Also collapse symbols with the same language, container, and name into one even if they are
+        # defined in different files (e.g., a symbol and its re-exports), keeping the definition. The
+        # number of symbols collapsed into each one is its duplicateCount. Defaults to false.
+        deduplicateByMoniker: Boolean
+        # The order of the symbols. Defaults to LOCATION.
+        orderBy: SymbolOrderBy
+        # Whether to reverse the order given by orderBy. Defaults to false.
+        descending: Boolean
+        # Skip this many symbols from the start of the list (for simple offset-based paging). This cannot be
+        # combined with the after and before cursors, and offset plus first must be at most 10,000 (or
+        # 100 if bodyQuery or since is given).
+        offset: Int
+        # If the symbols at this commit are not indexed, list the symbols at the commit that the
+        # repository's default branch is indexed at instead, which is faster (see
+        # SymbolConnection.fallbackCommit). Those symbols may be stale or unrelated to this commit, so
+        # clients should say so when this happens. Defaults to false.
+        useIndexedRevision: Boolean
+        # Return the symbols after this cursor (from a previous page's pageInfo.endCursor).
+        #
+        # Cursors hold the sort keys of a symbol (its path, position, and name, and the orderBy key),
+        # not its index in the list, so paging resumes at the same place in the order even if the list
+        # changed between requests (e.g., because the indexed revision changed). This is best-effort:
+        # if the cursor's symbol no longer exists, the page starts at the next symbol that sorts after
+        # it; symbols added before the cursor are not returned; and symbols whose position changed may
+        # be skipped or returned twice.
+        after: String
+        # Return the page of symbols immediately before this cursor (from a previous page's
+        # pageInfo.startCursor). Like after, this is best-effort if the list changed.
+        before: String
     ): SymbolConnection!
+    # Whether any symbols are defined (at the commit, or in this file or directory), for showing or
+    # hiding symbol lists. This fetches the first page of symbols with the default arguments, so a
+    # subsequent symbols request with the default arguments is served from the cache.
+    hasSymbols: Boolean!
     # Whether this tree entry is a single child
     isSingleChild(
         # Returns the first n files in the tree.
@@ -2795,11 +3498,122 @@ type GitBlob implements TreeEntry & File2 {
     submodule: Submodule
     # Symbols defined in this blob.
     symbols(
-        # Returns the first n symbols from the list.
+        # Returns the first n symbols from the list (at most 1,000 unless the site configuration's
+        # search.symbols.maxPageSize says otherwise).
         first: Int
-        # Return symbols matching the query.
+        # Return symbols matching the query. Unless regExp is true, a query that is a qualified name
+        # (e.g., "Server.Serve" or "vector::push_back") also matches against symbol names qualified by
+        # their container names, using the container separator of each symbol's language.
         query: String
+        # Whether the query is a regular expression (the default) or a literal string. Regular
+        # expressions use RE2 syntax and are matched against symbol names.
+        regexp: Boolean
+        # How a literal query is matched against symbol names (e.g., PREFIX for autocompletion). If
+        # set, the query is treated as a literal string, and regexp must not be true.
+        match: SymbolMatch
+        # Whether the query is matched case-sensitively. By default, it is matched case-insensitively.
+        caseSensitive: Boolean
+        # Return only symbols whose container name (see Symbol.containerName) matches this query, which
+        # is interpreted like the query argument (using regexp, match, and caseSensitive).
+        containerQuery: String
+        # Return only symbols in files whose paths match all of these regular expressions (like the
+        # file: search filter). Paths are relative to the repository root, and patterns are not
+        # implicitly anchored (e.g., use "^src/api/" for the symbols under the top-level src/api directory).
+        # Like the query, they are matched case-insensitively unless caseSensitive is true.
+        includePatterns: [String!]
+        # Omit symbols in files whose paths match any of these regular expressions (like the -file:
+        # search filter), matched like includePatterns.
+        excludePatterns: [String!]
+        # Return only symbols in these files (paths relative to the repository root), e.g., the files
+        # changed in a pull request. This lists the symbols of all of the files in one request, which is
+        # faster than a request per file. At most 500 paths may be given.
+        paths: [String!]
+        # Omit symbols in vendored and generated files (e.g., in vendor/ and node_modules/ directories),
+        # as determined by the site configuration's search.symbols.generatedPatterns. Defaults to false.
+        excludeGenerated: Boolean
+        # Return only symbols in files of any of these languages (e.g., "go" or "typescript"), as
+        # determined by their file extensions (like the lang: search filter).
+        languages: [String!]
+        # Return only symbols of these kinds. If empty or unset, symbols of all kinds are returned.
+        kinds: [SymbolKind!]
+        # Omit symbols that name references to definitions elsewhere (such as imported package names)
+        # rather than definitions, as determined by the site configuration's
+        # search.symbols.referenceKinds. Defaults to true.
+        definitionsOnly: Boolean
+        # Omit symbols that are declarations of symbols defined elsewhere (such as C function
+        # prototypes), keeping only their definitions (see Symbol.isDefinition). Defaults to false.
+        excludeDeclarations: Boolean
+        # Omit symbols whose visibility is PRIVATE (see Symbol.visibility), keeping exported symbols
+        # and those whose visibility is unknown. Defaults to false.
+        exportedOnly: Boolean
+        # Return only top-level symbols, which are those that aren't in a container (such as a
+        # class). Their nested symbols are still available as their children (see Symbol.children),
+        # so outlines can show the top-level symbols first and expand them lazily. Defaults to false.
+        topLevelOnly: Boolean
+        # Return only symbols whose bodies contain this string (or match it as a regular expression if
+        # regexp is true), case-insensitively unless caseSensitive is true. A symbol's body is the lines
+        # from its start through the end of its definition (e.g., a function's closing brace) if ctags
+        # reports it, and otherwise only its start line. This is expensive, because the file that
+        # defines each symbol must be read, so first must be at most 100 and the symbols must be
+        # defined in at most 50 files.
+        bodyQuery: String
+        # Return only symbols whose start lines were last changed (according to git blame) after this
+        # time: an RFC 3339 date, or a revision whose author date is used. Symbols that can't be blamed
+        # (e.g., those of directories) are kept. This is expensive, because each file that defines a
+        # symbol must be blamed, so first must be at most 100 and the symbols must be defined in at
+        # most 100 files.
+        since: String
+        # Omit symbols whose files don't exist at the commit (which can happen if the symbols source's
+        # index is stale). This requires listing the directories that contain the symbols, so it is off
+        # by default.
+        validatePaths: Boolean
+        # Collapse symbols with the same name, container, file, and position into one (the default). Set
+        # to false to return the raw list of symbols from the backend (e.g., for debugging).
+        deduplicate: Boolean
+        # Also collapse symbols with the same language, container, and name into one even if they are
+        # defined in different files (e.g., a symbol and its re-exports), keeping the definition. The
+        # number of symbols collapsed into each one is its duplicateCount. Defaults to false.
+        deduplicateByMoniker: Boolean
+        # The order of the symbols. Defaults to LOCATION.
+        orderBy: SymbolOrderBy
+        # Whether to reverse the order given by orderBy. Defaults to false.
+        descending: Boolean
+        # Skip this many symbols from the start of the list (for simple offset-based paging). This cannot be
+        # combined with the after and before cursors, and offset plus first must be at most 10,000 (or
+        # 100 if bodyQuery or since is given).
+        offset: Int
+        # If the symbols at this commit are not indexed, list the symbols at the commit that the
+        # repository's default branch is indexed at instead, which is faster (see
+        # SymbolConnection.fallbackCommit). Those symbols may be stale or unrelated to this commit, so
+        # clients should say so when this happens. Defaults to false.
+        useIndexedRevision: Boolean
+        # Return the symbols after this cursor (from a previous page's pageInfo.endCursor).
+        #
+        # Cursors hold the sort keys of a symbol (its path, position, and name, and the orderBy key),
+        # not its index in the list, so paging resumes at the same place in the order even if the list
+        # changed between requests (e.g., because the indexed revision changed). This is best-effort:
+        # if the cursor's symbol no longer exists, the page starts at the next symbol that sorts after
+        # it; symbols added before the cursor are not returned; and symbols whose position changed may
+        # be skipped or returned twice.
+        after: String
+        # Return the page of symbols immediately before this cursor (from a previous page's
+        # pageInfo.startCursor). Like after, this is best-effort if the list changed.
+        before: String
     ): SymbolConnection!
+    # Whether any symbols are defined (at the commit, or in this file or directory), for showing or
+    # hiding symbol lists. This fetches the first page of symbols with the default arguments, so a
+    # subsequent symbols request with the default arguments is served from the cache.
+    hasSymbols: Boolean!
+    # The symbol in this file whose range contains the given position, or null if there is none. A
+    # symbol's range is the range of its name where it is defined (see Symbol.location), so this is the
+    # symbol being defined if the position is on the name in its definition. If multiple ranges contain
+    # the position, the symbol with the smallest range is returned.
+    symbolAtPosition(
+        # The line (zero-based) of the position.
+        line: Int!
+        # The character (zero-based) of the position.
+        character: Int!
+    ): Symbol
     # Always false, since a blob is a file, not directory.
     isSingleChild(
         # Returns the first n files in the tree.
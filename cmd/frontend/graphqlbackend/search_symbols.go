@@ -357,61 +357,58 @@ func ctagsSymbolCharacter(s protocol.Symbol) int {
 	return 0
 }
 
+// ctagsKindsByLSPSymbolKind lists the ctags kinds (in lowercase, from any
+// parser) that map to each LSP symbol kind. This is the mapping table for all
+// languages. Per-language overrides from the site config are applied by
+// symbolLSPKind.
+var ctagsKindsByLSPSymbolKind = map[lsp.SymbolKind][]string{
+	lsp.SKFile:          {"file"},
+	lsp.SKModule:        {"module"},
+	lsp.SKNamespace:     {"namespace"},
+	lsp.SKPackage:       {"package", "packagename", "subprogspec"},
+	lsp.SKClass:         {"class", "type", "service", "typedef", "union", "section", "subtype", "component"},
+	lsp.SKMethod:        {"method", "methodspec"},
+	lsp.SKProperty:      {"property"},
+	lsp.SKField:         {"field", "member", "anonmember", "recordfield"},
+	lsp.SKConstructor:   {"constructor"},
+	lsp.SKEnum:          {"enum", "enumerator"},
+	lsp.SKInterface:     {"interface"},
+	lsp.SKFunction:      {"function", "func", "subroutine", "macro", "subprogram", "procedure", "command", "singletonmethod", "prototype"},
+	lsp.SKVariable:      {"variable", "var", "functionvar", "define", "alias", "val", "externvar"},
+	lsp.SKConstant:      {"constant", "const"},
+	lsp.SKString:        {"string", "message", "heredoc"},
+	lsp.SKNumber:        {"number"},
+	lsp.SKBoolean:       {"bool", "boolean"},
+	lsp.SKArray:         {"array"},
+	lsp.SKObject:        {"object", "literal", "map"},
+	lsp.SKKey:           {"key", "label", "target", "selector", "id", "tag"},
+	lsp.SKNull:          {"null"},
+	lsp.SKEnumMember:    {"enum member", "enumconstant"},
+	lsp.SKStruct:        {"struct"},
+	lsp.SKEvent:         {"event"},
+	lsp.SKOperator:      {"operator"},
+	lsp.SKTypeParameter: {"type parameter", "annotation"},
+}
+
+// lspSymbolKindsByCtagsKind is the inverse of ctagsKindsByLSPSymbolKind.
+var lspSymbolKindsByCtagsKind = func() map[string]lsp.SymbolKind {
+	m := map[string]lsp.SymbolKind{}
+	for kind, ctagsKinds := range ctagsKindsByLSPSymbolKind {
+		for _, ctagsKind := range ctagsKinds {
+			m[ctagsKind] = kind
+		}
+	}
+	return m
+}()
+
+// ctagsKindToLSPSymbolKind maps a ctags kind (case-insensitive) to the closest
+// LSP symbol kind (see ctagsKindsByLSPSymbolKind), or 0 if it is unknown.
+// symbolLSPKind, which also applies the per-language overrides from the site
+// config, should be used instead where the symbol's language is known.
 func ctagsKindToLSPSymbolKind(kind string) lsp.SymbolKind {
 	// Ctags kinds are determined by the parser and do not (in general) match LSP symbol kinds.
-	switch strings.ToLower(kind) {
-	case "file":
-		return lsp.SKFile
-	case "module":
-		return lsp.SKModule
-	case "namespace":
-		return lsp.SKNamespace
-	case "package", "packagename", "subprogspec":
-		return lsp.SKPackage
-	case "class", "type", "service", "typedef", "union", "section", "subtype", "component":
-		return lsp.SKClass
-	case "method", "methodspec":
-		return lsp.SKMethod
-	case "property":
-		return lsp.SKProperty
-	case "field", "member", "anonmember", "recordfield":
-		return lsp.SKField
-	case "constructor":
-		return lsp.SKConstructor
-	case "enum", "enumerator":
-		return lsp.SKEnum
-	case "interface":
-		return lsp.SKInterface
-	case "function", "func", "subroutine", "macro", "subprogram", "procedure", "command", "singletonmethod":
-		return lsp.SKFunction
-	case "variable", "var", "functionvar", "define", "alias", "val":
-		return lsp.SKVariable
-	case "constant", "const":
-		return lsp.SKConstant
-	case "string", "message", "heredoc":
-		return lsp.SKString
-	case "number":
-		return lsp.SKNumber
-	case "bool", "boolean":
-		return lsp.SKBoolean
-	case "array":
-		return lsp.SKArray
-	case "object", "literal", "map":
-		return lsp.SKObject
-	case "key", "label", "target", "selector", "id", "tag":
-		return lsp.SKKey
-	case "null":
-		return lsp.SKNull
-	case "enum member", "enumconstant":
-		return lsp.SKEnumMember
-	case "struct":
-		return lsp.SKStruct
-	case "event":
-		return lsp.SKEvent
-	case "operator":
-		return lsp.SKOperator
-	case "type parameter", "annotation":
-		return lsp.SKTypeParameter
+	if k, ok := lspSymbolKindsByCtagsKind[strings.ToLower(kind)]; ok {
+		return k
 	}
 	log15.Debug("Unknown ctags kind", "kind", kind)
 	return 0
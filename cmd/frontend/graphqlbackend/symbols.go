@@ -2,46 +2,383 @@ package graphqlbackend
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"regexp"
 	"regexp/syntax"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/zoekt"
 	zoektquery "github.com/google/zoekt/query"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/inconshreveable/log15"
+	lsp "github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gituri"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	symbolsclient "github.com/sourcegraph/sourcegraph/internal/symbols"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 )
 
 type symbolsArgs struct {
 	graphqlutil.ConnectionArgs
-	Query           *string
-	IncludePatterns *[]string
+	Query                *string
+	RegExp               *bool
+	Match                *string
+	ContainerQuery       *string
+	CaseSensitive        *bool
+	IncludePatterns      *[]string
+	ExcludePatterns      *[]string
+	Paths                *[]string
+	ExcludeGenerated     *bool
+	Languages            *[]string
+	Kinds                *[]string // SymbolKind enum names
+	DefinitionsOnly      *bool
+	ExcludeDeclarations  *bool
+	Since                *string
+	ExportedOnly         *bool
+	TopLevelOnly         *bool
+	BodyQuery            *string
+	UseIndexedRevision   *bool
+	ValidatePaths        *bool
+	Deduplicate          *bool
+	DeduplicateByMoniker *bool
+	OrderBy              *string // SymbolOrderBy enum value
+	Descending           *bool
+	Offset               *int32
+	After                *string
+	Before               *string
+
+	// exact is whether the symbols sources are asked for exactly First symbols, instead of one
+	// more to determine whether there are more (see symbolsSourceLimit). It isn't a GraphQL
+	// argument: it is set by callers that don't use the limitHit of the results.
+	exact bool
 }
 
 func (r *GitTreeEntryResolver) Symbols(ctx context.Context, args *symbolsArgs) (*symbolConnectionResolver, error) {
-	symbols, err := computeSymbols(ctx, r.commit, args.Query, args.First, args.IncludePatterns)
-	if err != nil && len(symbols) == 0 {
+	return newSymbolConnectionResolver(ctx, r.commit, r.Path(), args)
+}
+
+// HasSymbols reports whether any symbols are defined in this file or directory.
+func (r *GitTreeEntryResolver) HasSymbols(ctx context.Context) (bool, error) {
+	return hasSymbols(ctx, r.commit, r.Path())
+}
+
+// SymbolAtPosition returns the symbol in this file whose range contains the position, or nil if
+// there is none. If multiple symbols' ranges contain the position, the one with the smallest range
+// is returned.
+func (r *GitTreeEntryResolver) SymbolAtPosition(ctx context.Context, args *struct {
+	Line      int32
+	Character int32
+}) (*symbolResolver, error) {
+	first := int32(symbolsCountLimit)
+	symbols, _, err := computeSymbols(ctx, r.commit, r.Path(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, exact: true})
+	if err != nil {
 		return nil, err
 	}
-	return &symbolConnectionResolver{symbols: symbols, first: args.First}, nil
+	return symbolAtPosition(symbols, lsp.Position{Line: int(args.Line), Character: int(args.Character)}), nil
+}
+
+// symbolAtPosition returns the symbol with the smallest range that contains pos, or nil if none
+// does.
+func symbolAtPosition(symbols []*symbolResolver, pos lsp.Position) *symbolResolver {
+	var best *symbolResolver
+	for _, symbol := range symbols {
+		rng := symbol.location.lspRange
+		if rng == nil || !rangeContains(*rng, pos) {
+			continue
+		}
+		if best == nil || rangeWithin(*rng, *best.location.lspRange) {
+			best = symbol
+		}
+	}
+	return best
+}
+
+// rangeContains reports whether pos is in rng (whose end is exclusive).
+func rangeContains(rng lsp.Range, pos lsp.Position) bool {
+	return !positionLess(pos, rng.Start) && positionLess(pos, rng.End)
+}
+
+// rangeWithin reports whether inner is strictly smaller than and nested in outer.
+func rangeWithin(inner, outer lsp.Range) bool {
+	return inner != outer && !positionLess(inner.Start, outer.Start) && !positionLess(outer.End, inner.End)
+}
+
+func positionLess(a, b lsp.Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Character < b.Character
 }
 
 func (r *GitCommitResolver) Symbols(ctx context.Context, args *symbolsArgs) (*symbolConnectionResolver, error) {
-	symbols, err := computeSymbols(ctx, r, args.Query, args.First, args.IncludePatterns)
-	if err != nil && len(symbols) == 0 {
+	return newSymbolConnectionResolver(ctx, r, "", args)
+}
+
+// HasSymbols reports whether any symbols are defined as of this commit.
+func (r *GitCommitResolver) HasSymbols(ctx context.Context) (bool, error) {
+	return hasSymbols(ctx, r, "")
+}
+
+// hasSymbols reports whether any symbols are defined in commit at path. It
+// computes the symbols that a symbol connection with the default arguments is
+// computed from (instead of a limit of 1), because that's what a client that
+// shows the symbols next is likely to request, and then that request is served
+// from the cache.
+func hasSymbols(ctx context.Context, commit *GitCommitResolver, path string) (bool, error) {
+	first := int32(symbolsWindowLimit(&symbolsArgs{}))
+	symbols, _, err := computeSymbols(ctx, commit, path, &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}})
+	if err != nil {
+		return false, err
+	}
+	return len(symbols) > 0, nil
+}
+
+// Symbol returns the first symbol (in the order of their locations) defined as of this commit
+// with the given name and, if given, container name, or nil if there is none.
+func (r *GitCommitResolver) Symbol(ctx context.Context, args *struct {
+	Name          string
+	ContainerName *string
+}) (*symbolResolver, error) {
+	var (
+		first         = int32(symbolsCountLimit)
+		query         = "^" + regexp.QuoteMeta(args.Name) + "$"
+		caseSensitive = true
+	)
+	symbols, _, err := computeSymbols(ctx, r, "", &symbolsArgs{
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+		Query:          &query,
+		CaseSensitive:  &caseSensitive,
+		exact:          true,
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &symbolConnectionResolver{symbols: symbols, first: args.First}, nil
+	for _, symbol := range symbols {
+		if args.ContainerName == nil || symbol.symbol.Parent == *args.ContainerName {
+			return symbol, nil
+		}
+	}
+	return nil, nil
+}
+
+func newSymbolConnectionResolver(ctx context.Context, commit *GitCommitResolver, path string, args *symbolsArgs) (*symbolConnectionResolver, error) {
+	if err := validateSymbolsFirst(args.First); err != nil {
+		return nil, err
+	}
+	if err := validateSymbolsBodyQuery(args); err != nil {
+		return nil, err
+	}
+	if err := validateSymbolsSince(args); err != nil {
+		return nil, err
+	}
+	after, err := unmarshalSymbolCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+	before, err := unmarshalSymbolCursor(args.Before)
+	if err != nil {
+		return nil, err
+	}
+	window := symbolsWindowLimit(args)
+	offset, err := symbolsOffset(args.Offset, args.First, window, after != nil || before != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The sources return the symbols in their own order, so every page (with cursors, an offset,
+	// or neither) is taken from the same ordered window of symbols. Otherwise the first page would
+	// be ordered from fewer symbols than the pages after it, and symbols could be skipped or
+	// repeated.
+	computeArgs := *args
+	first := int32(window)
+	computeArgs.First = &first
+	var fallbackCommit *GitCommitResolver
+	if args.UseIndexedRevision != nil && *args.UseIndexedRevision {
+		repo := indexedSymbolsRepository(ctx, string(commit.repo.repo.Name))
+		if version, ok := indexedSymbolsFallback(repo, string(commit.oid)); ok {
+			fallbackCommit = &GitCommitResolver{repo: commit.repo, oid: GitObjectID(version)}
+			commit = fallbackCommit
+		}
+	}
+	symbols, common, err := computeSymbols(ctx, commit, path, &computeArgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(symbols) > window {
+		// The extra symbol only shows that the window has more symbols (see symbolsSourceLimit).
+		symbols, common.limitHit = symbols[:window], true
+	}
+	all := symbols
+	order, err := newSymbolOrder(args)
+	if err != nil {
+		return nil, err
+	}
+	symbols, omittedStart, omittedEnd := symbolsBetweenCursors(symbols, after, before, order)
+	if offset > 0 {
+		if offset > len(symbols) {
+			offset = len(symbols)
+		}
+		symbols, omittedStart = symbols[offset:], true
+	}
+	return &symbolConnectionResolver{
+		commit:          commit,
+		path:            path,
+		args:            args,
+		first:           args.First,
+		all:             all,
+		symbols:         symbols,
+		fromEnd:         before != nil && after == nil,
+		hasPreviousPage: omittedStart,
+		hasNextPage:     omittedEnd || (common.limitHit && before == nil),
+		sourceLimitHit:  common.limitHit,
+		sourceErrors:    common.sourceErrors,
+		timings:         common.timings,
+		unavailable:     common.unavailable,
+		fallbackCommit:  fallbackCommit,
+	}, nil
 }
 
 type symbolConnectionResolver struct {
-	first   *int32
+	// commit, path, and args are the inputs that symbols was computed from. They are retained so
+	// that fields like totalCount can recompute the symbols with a different limit.
+	commit *GitCommitResolver
+	path   string
+	args   *symbolsArgs
+
+	first *int32
+
+	// all are the ordered symbols of the window that the page is taken from, before the cursors
+	// and offset are applied (see symbolsWindowLimit).
+	all []*symbolResolver
+
+	// symbols are the symbols from the cursors and offset on, of which the first are on the page.
 	symbols []*symbolResolver
+
+	// fromEnd is whether the page is taken from the end of symbols (when paginating backwards
+	// with a before cursor) instead of the start.
+	fromEnd bool
+
+	// hasPreviousPage and hasNextPage are whether symbols were omitted from the start or end of
+	// symbols, either because of the after and before cursors or (for hasNextPage) because the
+	// symbols source hit its limit.
+	hasPreviousPage, hasNextPage bool
+
+	// sourceLimitHit is whether the symbols source returned more symbols than the limit, before
+	// filtering (see symbolsCommon.limitHit).
+	sourceLimitHit bool
+
+	// sourceErrors are the failures of symbols sources that did not prevent returning symbols.
+	sourceErrors []*symbolSourceError
+
+	// timings are the durations of the calls to the symbols sources.
+	timings []*symbolSourceTiming
+
+	// unavailable is whether no symbols source could list the symbols (see symbolsCommon).
+	unavailable bool
+
+	// fallbackCommit is the indexed commit that the symbols were computed at instead of the
+	// requested commit (see the useIndexedRevision argument), or nil. If set, it is also commit.
+	fallbackCommit *GitCommitResolver
+}
+
+// Truncated returns whether symbols were omitted because of the limit given by first: either more
+// symbols (between the cursors, if any) satisfy the arguments than are on the page, or the symbols
+// source returned more symbols than the limit before they were filtered, so there may be more.
+// Unlike hasNextPage, it doesn't depend on the direction of paging.
+func (r *symbolConnectionResolver) Truncated() bool {
+	return len(r.symbols) > limitOrDefault(r.first) || r.sourceLimitHit
+}
+
+// Unavailable returns whether no symbols source could list the symbols, so the connection has no
+// symbols even though some may match. The reasons are in Errors.
+func (r *symbolConnectionResolver) Unavailable() bool { return r.unavailable }
+
+// FallbackCommit returns the indexed commit that the symbols were listed at instead of the
+// requested commit, or nil if they were listed at the requested commit.
+func (r *symbolConnectionResolver) FallbackCommit() *GitCommitResolver { return r.fallbackCommit }
+
+// symbolKindCountResolver is the number of symbols of a kind in a symbol
+// connection.
+type symbolKindCountResolver struct {
+	kind  string // a SymbolKind enum value
+	count int32
+}
+
+func (r *symbolKindCountResolver) Kind() string { return r.kind }
+
+func (r *symbolKindCountResolver) Count() int32 { return r.count }
+
+// symbolsSourceLimit returns the number of symbols to request from the
+// symbols sources: one more than the limit, so that it can be determined
+// whether there are more symbols (see symbolsCommon.limitHit), unless the
+// caller asked for exactly the limit.
+func symbolsSourceLimit(args *symbolsArgs) int {
+	if args.exact {
+		return limitOrDefault(args.First)
+	}
+	return limitOrDefault(args.First) + 1
+}
+
+// symbolsCountLimit is the maximum number of symbols that are fetched to count
+// the total number of symbols in a connection.
+const symbolsCountLimit = 10000
+
+// symbolsWindowLimit returns the number of symbols that a symbol connection is
+// computed from: all pages are taken from these symbols (after they are
+// ordered). It is symbolsCountLimit unless the bodyQuery or since arguments are
+// given, which read or blame the file of every symbol considered, so they are
+// limited to their maximum values of first.
+func symbolsWindowLimit(args *symbolsArgs) int {
+	limit := symbolsCountLimit
+	if args.BodyQuery != nil && *args.BodyQuery != "" && limit > maxSymbolsBodyQueryFirst {
+		limit = maxSymbolsBodyQueryFirst
+	}
+	if args.Since != nil && *args.Since != "" && limit > maxSymbolsSinceFirst {
+		limit = maxSymbolsSinceFirst
+	}
+	return limit
+}
+
+// validateSymbolsFirst returns an error if a client requested more symbols at
+// a time than the site config "search.symbols.maxPageSize" allows (or a
+// negative number of symbols).
+func validateSymbolsFirst(first *int32) error {
+	if first == nil {
+		return nil
+	}
+	if max := conf.SearchSymbolsMaxPageSize(); *first < 0 || int(*first) > max {
+		return fmt.Errorf("symbols: requested 'first' value outside allowed range (0 - %d)", max)
+	}
+	return nil
+}
+
+// symbolsOffset validates and returns the offset argument of a symbols
+// request (0 if unset). The page must be within the window of symbols that it
+// is taken from (see symbolsWindowLimit).
+func symbolsOffset(offset, first *int32, window int, hasCursor bool) (int, error) {
+	if offset == nil {
+		return 0, nil
+	}
+	if hasCursor {
+		return 0, errors.New("symbols: 'offset' cannot be combined with the 'after' and 'before' cursors")
+	}
+	if *offset < 0 || int(*offset)+limitOrDefault(first) > window {
+		return 0, fmt.Errorf("symbols: 'offset' plus 'first' must be between 0 and %d", window)
+	}
+	return int(*offset), nil
 }
 
 func limitOrDefault(first *int32) int {
@@ -54,35 +391,65 @@ func limitOrDefault(first *int32) int {
 // indexedSymbols checks to see if Zoekt has indexed
 // symbols information for a repository at a specific
 // commit.
-func indexedSymbols(repository, commit string) bool {
+func indexedSymbols(ctx context.Context, repository, commit string) bool {
+	repo := indexedSymbolsRepository(ctx, repository)
+	if repo == nil {
+		return false
+	}
+	for _, branch := range repo.Branches {
+		if branch.Version == commit {
+			return true
+		}
+	}
+	return false
+}
+
+// indexedSymbolsRepository returns the indexed search entry for the repository,
+// or nil if indexed search is disabled or the repository's symbols are not
+// indexed.
+func indexedSymbolsRepository(ctx context.Context, repository string) *zoekt.Repository {
 	z := search.Indexed()
 	if !z.Enabled() {
-		return false
+		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
 	defer cancel()
 	set, err := z.ListAll(ctx)
 	if err != nil {
-		return false
+		return nil
 	}
 
 	repo, ok := set[strings.ToLower(repository)]
 	if !ok || !repo.HasSymbols {
-		return false
+		return nil
 	}
+	return repo
+}
 
+// indexedSymbolsFallback returns the commit to list symbols at instead of
+// commit, for the useIndexedRevision argument: the indexed commit of the
+// repository's default branch (the first indexed branch), if commit is not
+// indexed. It is not necessarily an ancestor of commit, so its symbols may be
+// stale or unrelated. It returns false if commit is indexed or there is no
+// indexed commit to fall back to.
+func indexedSymbolsFallback(repo *zoekt.Repository, commit string) (string, bool) {
+	if repo == nil || len(repo.Branches) == 0 {
+		return "", false
+	}
 	for _, branch := range repo.Branches {
 		if branch.Version == commit {
-			return true
+			return "", false
 		}
 	}
-
-	return false
+	return repo.Branches[0].Version, true
 }
 
-func searchZoektSymbols(ctx context.Context, commit *GitCommitResolver, queryString *string, first *int32, includePatterns *[]string) (res []*symbolResolver, err error) {
-	raw := *queryString
+func searchZoektSymbols(ctx context.Context, commit *GitCommitResolver, path string, q *symbolQuery, limit int, paths *symbolPathPatterns) (res []*symbolResolver, err error) {
+	ctx, done := context.WithTimeout(ctx, conf.SearchSymbolsTimeout())
+	defer done()
+
+	raw := q.pattern
 	if raw == "" {
 		raw = ".*"
 	}
@@ -95,13 +462,15 @@ func searchZoektSymbols(ctx context.Context, commit *GitCommitResolver, queryStr
 	var query zoektquery.Q
 	if expr.Op == syntax.OpLiteral {
 		query = &zoektquery.Substring{
-			Pattern: string(expr.Rune),
-			Content: true,
+			Pattern:       string(expr.Rune),
+			Content:       true,
+			CaseSensitive: q.caseSensitive,
 		}
 	} else {
 		query = &zoektquery.Regexp{
-			Regexp:  expr,
-			Content: true,
+			Regexp:        expr,
+			Content:       true,
+			CaseSensitive: q.caseSensitive,
 		}
 	}
 
@@ -110,23 +479,44 @@ func searchZoektSymbols(ctx context.Context, commit *GitCommitResolver, queryStr
 		string(commit.repo.repo.Name): true,
 	}}
 	ands := []zoektquery.Q{repo, sym}
-	for _, p := range *includePatterns {
-		q, err := fileRe(p, true)
+	if path = strings.Trim(path, "/"); path != "" {
+		// Only return the symbols in the file or directory at path.
+		q, err := fileRe("^"+regexp.QuoteMeta(path)+"(/|$)", true)
+		if err != nil {
+			return nil, err
+		}
+		ands = append(ands, q)
+	}
+	if len(paths.files) > 0 {
+		q, err := fileRe(paths.filesPattern(), true)
 		if err != nil {
 			return nil, err
 		}
 		ands = append(ands, q)
 	}
+	for _, p := range paths.include {
+		q, err := fileRe(p, paths.caseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		ands = append(ands, q)
+	}
+	if exclude := paths.excludePattern(); exclude != "" {
+		q, err := fileRe(exclude, paths.caseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		ands = append(ands, &zoektquery.Not{Child: q})
+	}
 
 	final := zoektquery.Simplify(zoektquery.NewAnd(ands...))
-	match := limitOrDefault(first) + 1
 	resp, err := search.Indexed().Client.Search(ctx, final, &zoekt.SearchOptions{
 		MaxWallTime:            3 * time.Second,
-		ShardMaxMatchCount:     match * 25,
-		TotalMaxMatchCount:     match * 25,
-		ShardMaxImportantMatch: match * 25,
-		TotalMaxImportantMatch: match * 25,
-		MaxDocDisplayCount:     match,
+		ShardMaxMatchCount:     limit * 25,
+		TotalMaxMatchCount:     limit * 25,
+		ShardMaxImportantMatch: limit * 25,
+		TotalMaxImportantMatch: limit * 25,
+		MaxDocDisplayCount:     limit,
 	})
 	if err != nil {
 		return nil, err
@@ -163,37 +553,278 @@ func searchZoektSymbols(ctx context.Context, commit *GitCommitResolver, queryStr
 	return
 }
 
-func computeSymbols(ctx context.Context, commit *GitCommitResolver, query *string, first *int32, includePatterns *[]string) (res []*symbolResolver, err error) {
-	if indexedSymbols(string(commit.repo.repo.Name), string(commit.oid)) {
-		return searchZoektSymbols(ctx, commit, query, first, includePatterns)
+// symbolsCommon contains fields that describe how the symbols returned by
+// computeSymbols were computed.
+type symbolsCommon struct {
+	// limitHit is whether the symbols source returned more symbols than the
+	// requested limit before any filtering, which means that there may be more
+	// symbols than those returned (even if fewer than the limit are returned).
+	limitHit bool
+
+	// sourceErrors are the failures of symbols sources that did not prevent
+	// computeSymbols from returning (possibly partial) results.
+	sourceErrors []*symbolSourceError
+
+	// timings are the durations of the calls to the symbols sources.
+	timings []*symbolSourceTiming
+
+	// unavailable is whether no symbols source could list the symbols (because
+	// none is configured or the query was too short for all of them), so that
+	// the lack of results doesn't mean that there are no matching symbols.
+	unavailable bool
+}
+
+// The names of the symbols sources, as reported in symbol source errors.
+const (
+	symbolSourceIndexedSearch  = "indexed search"
+	symbolSourceSymbolsService = "symbols service"
+)
+
+// symbolSourceError is a failure of one of the symbols sources.
+type symbolSourceError struct {
+	source string
+	err    error
+}
+
+func (e *symbolSourceError) Error() string { return e.source + ": " + e.err.Error() }
+
+func (e *symbolSourceError) Source() string { return e.source }
+
+func (e *symbolSourceError) Message() string { return e.err.Error() }
+
+// computeSymbols returns the symbols defined in commit. If path is non-empty,
+// only symbols defined in the file or directory at path are returned.
+//
+// A non-nil error means that no usable results could be computed. Failures of
+// symbols sources that still allow returning results (e.g., when indexed
+// search fails but the symbols service succeeds, or when the symbols service
+// returns partial results) are reported in common.sourceErrors instead.
+//
+// The results are cached (see symbolsCache).
+func computeSymbols(ctx context.Context, commit *GitCommitResolver, path string, args *symbolsArgs) ([]*symbolResolver, symbolsCommon, error) {
+	res, common, err := computeSymbolsCached(ctx, commit, path, args)
+	if args.TopLevelOnly != nil && *args.TopLevelOnly {
+		// Filter after the symbols are linked (see linkFileSymbols), so that the Children of the
+		// top-level symbols are still the symbols nested in them.
+		res = filterNestedSymbols(res)
 	}
+	return res, common, err
+}
 
-	ctx, done := context.WithTimeout(ctx, 5*time.Second)
-	defer done()
+// computeSymbolsCached is computeSymbols, except for the top-level filter. Its
+// results are cached for the TTL given by the site config
+// "search.symbols.cacheTTL" (see symbolsCache).
+func computeSymbolsCached(ctx context.Context, commit *GitCommitResolver, path string, args *symbolsArgs) ([]*symbolResolver, symbolsCommon, error) {
+	ttl := conf.SearchSymbolsCacheTTL()
+	if ttl == 0 {
+		return computeSymbolsLimited(ctx, commit, path, args)
+	}
+
+	key, err := symbolsCacheKey(commit, path, args)
+	if err != nil {
+		return nil, symbolsCommon{}, err
+	}
+	start := time.Now()
+	if entry, ok := getSymbolsCacheEntry(key); ok {
+		res, common := entry.resolvers(commit), entry.common
+		common.timings = nil
+		common.recordTiming(symbolSourceCache, start, len(res), nil)
+		return res, common, nil
+	}
+
+	res, common, err := computeSymbolsLimited(ctx, commit, path, args)
+	if err == nil && len(common.sourceErrors) == 0 && ctx.Err() == nil {
+		// Only cache complete results, so that transient failures aren't cached.
+		addSymbolsCacheEntry(key, newSymbolsCacheEntry(res, common, ttl))
+	}
+	return res, common, err
+}
+
+// computeSymbolsLimited is computeSymbolsUncached, subject to the limit on the
+// number of symbol lists computed at the same time (see symbolsLimiter).
+func computeSymbolsLimited(ctx context.Context, commit *GitCommitResolver, path string, args *symbolsArgs) ([]*symbolResolver, symbolsCommon, error) {
+	if err := symbolsComputeLimiter.acquire(); err != nil {
+		return nil, symbolsCommon{}, err
+	}
+	defer symbolsComputeLimiter.release()
+	return computeSymbolsUncached(ctx, commit, path, args)
+}
+
+func computeSymbolsUncached(ctx context.Context, commit *GitCommitResolver, path string, args *symbolsArgs) (res []*symbolResolver, common symbolsCommon, err error) {
+	q, err := newSymbolQuery(args)
+	if err != nil {
+		return nil, common, err
+	}
+	containerQuery, err := newSymbolContainerQuery(args)
+	if err != nil {
+		return nil, common, err
+	}
+	kinds, err := symbolKindSet(args.Kinds)
+	if err != nil {
+		return nil, common, err
+	}
+	paths, err := newSymbolPathPatterns(args)
+	if err != nil {
+		return nil, common, err
+	}
+	order, err := newSymbolOrder(args)
+	if err != nil {
+		return nil, common, err
+	}
+	// limit is the number of symbols (before filtering) that are ordered, and sourceLimit is the
+	// number requested from the sources. The sources return the symbols in their own order, so
+	// any order other than by location is applied to all of the symbols (up to
+	// symbolsWindowLimit) and not just to the first ones that the sources returned; otherwise the
+	// first symbols by name, say, would be wrong.
+	limit, sourceLimit := limitOrDefault(args.First), symbolsSourceLimit(args)
+	if order.by != symbolOrderByLocation || order.descending {
+		if window := symbolsWindowLimit(args); window > limit {
+			limit, sourceLimit = window, window+1
+		}
+	}
+	bodyQuery, err := newSymbolBodyQuery(args)
+	if err != nil {
+		return nil, common, err
+	}
+	since, err := newSymbolsSince(ctx, commit, args.Since)
+	if err != nil {
+		return nil, common, err
+	}
+	noise := newSymbolNoisePatterns()
 	defer func() {
-		if ctx.Err() != nil && len(res) == 0 {
-			err = errors.New("processing symbols is taking longer than expected. Try again in a while")
+		common.limitHit = len(res) > limit
+		res = filterNoiseSymbols(res, noise)
+		res = filterSymbolsByPath(res, path)
+		res = filterSymbolsByPathPatterns(res, paths)
+		res = filterSymbolsByQuery(res, q)
+		res = filterSymbolsByContainerQuery(res, containerQuery)
+		res = filterSymbolsByKind(res, kinds)
+		if args.DefinitionsOnly == nil || *args.DefinitionsOnly {
+			res = filterReferenceSymbols(res, conf.SearchSymbolsReferenceKinds())
+		}
+		if args.ExcludeDeclarations != nil && *args.ExcludeDeclarations {
+			res = filterDeclarationSymbols(res)
+		}
+		if args.ExportedOnly != nil && *args.ExportedOnly {
+			res = filterPrivateSymbols(res)
+		}
+		if bodyQuery.re != nil && err == nil {
+			var bodyErr error
+			if res, bodyErr = filterSymbolsByBody(ctx, res, bodyQuery); bodyErr != nil {
+				res, err = nil, bodyErr
+			}
+		}
+		if since != nil && err == nil {
+			var sinceErr error
+			if res, sinceErr = filterSymbolsSince(ctx, commit, res, *since); sinceErr != nil {
+				res, err = nil, sinceErr
+			}
+		}
+		if args.ValidatePaths != nil && *args.ValidatePaths && err == nil {
+			var validateErr error
+			if res, validateErr = filterSymbolsWithValidPaths(ctx, commit, res); validateErr != nil {
+				res, err = nil, validateErr
+			}
+		}
+		if args.Deduplicate == nil || *args.Deduplicate {
+			res = dedupeSymbols(res)
+		}
+		if args.DeduplicateByMoniker != nil && *args.DeduplicateByMoniker {
+			res = dedupeSymbolsByMoniker(res)
 		}
+		if args.Query != nil && *args.Query != "" {
+			scoreSymbols(res, *args.Query, q.qualified)
+		}
+		sortSymbols(res, order)
+		if n := symbolsSourceLimit(args); len(res) > n {
+			// Keep the extra symbol (if any) that shows that there are more than first.
+			res = res[:n]
+		}
+		linkFileSymbols(res)
 	}()
-	var includePatternsSlice []string
-	if includePatterns != nil {
-		includePatternsSlice = *includePatterns
+
+	if err := ctx.Err(); err != nil {
+		// The request was abandoned, so don't start any backend work.
+		return nil, common, err
+	}
+	minQueryLength := conf.SearchSymbolsMinQueryLength()
+	useIndexed := indexedSymbols(ctx, string(commit.repo.repo.Name), string(commit.oid))
+	if useIndexed {
+		if err := checkSymbolsQueryLength(symbolSourceIndexedSearch, minQueryLength.IndexedSearch, path, args); err != nil {
+			// Fall back to the symbols service, which may accept shorter queries.
+			common.sourceErrors = append(common.sourceErrors, &symbolSourceError{source: symbolSourceIndexedSearch, err: err})
+			useIndexed = false
+		}
+	}
+	if useIndexed {
+		start := time.Now()
+		res, err = searchZoektSymbols(ctx, commit, path, q, sourceLimit, paths)
+		common.recordTiming(symbolSourceIndexedSearch, start, len(res), err)
+		if err == nil || ctx.Err() != nil {
+			return res, common, err
+		}
+		// Don't fail the whole query because the index is unhealthy. The symbols service can
+		// compute the same symbols (more slowly), so only report an error if it fails too.
+		log15.Warn("Indexed symbol search failed, falling back to the symbols service.", "repo", commit.repo.repo.Name, "commit", commit.oid, "error", err)
+		common.sourceErrors = append(common.sourceErrors, &symbolSourceError{source: symbolSourceIndexedSearch, err: err})
+	}
+
+	if err := checkSymbolsQueryLength(symbolSourceSymbolsService, minQueryLength.SymbolsService, path, args); err != nil {
+		common.sourceErrors = append(common.sourceErrors, &symbolSourceError{source: symbolSourceSymbolsService, err: err})
+		common.unavailable = true
+		return nil, common, nil
 	}
 
+	parentCtx := ctx
+	ctx, done := context.WithTimeout(ctx, conf.SearchSymbolsTimeout())
+	defer done()
+	defer func() {
+		if parentCtx.Err() != nil {
+			// The caller cancelled the request (or its deadline passed), so report that
+			// rather than suggesting that the caller try again.
+			err = parentCtx.Err()
+			return
+		}
+		if ctx.Err() != nil {
+			err = errors.New("processing symbols is taking longer than expected. Try again in a while")
+		}
+		if err == symbolsclient.ErrNotConfigured {
+			// Deployments without a symbols service can still use the rest of the API, so
+			// report the missing source instead of failing the query.
+			common.sourceErrors = append(common.sourceErrors, &symbolSourceError{source: symbolSourceSymbolsService, err: err})
+			common.unavailable = true
+			err = nil
+		}
+		if err != nil && len(res) > 0 {
+			// Return the partial results and report the failure alongside them.
+			common.sourceErrors = append(common.sourceErrors, &symbolSourceError{source: symbolSourceSymbolsService, err: err})
+			err = nil
+		}
+	}()
 	searchArgs := search.SymbolsParameters{
 		CommitID:        api.CommitID(commit.oid),
-		First:           limitOrDefault(first) + 1, // add 1 so we can determine PageInfo.hasNextPage
+		First:           sourceLimit,
 		Repo:            commit.repo.repo.Name,
-		IncludePatterns: includePatternsSlice,
+		IncludePatterns: paths.include,
+		ExcludePattern:  paths.excludePattern(),
+		Paths:           paths.files,
+		Path:            strings.Trim(path, "/"),
+		Query:           q.pattern,
+		IsCaseSensitive: q.caseSensitive,
 	}
-	if query != nil {
-		searchArgs.Query = *query
+	if ctagsKinds, ok := ctagsKindsOfSymbolKinds(kinds); ok {
+		// Let the symbols service skip the symbols of other kinds, so that the limit applies to
+		// symbols of the requested kinds. They are still filtered by kind afterward, because
+		// older symbols services ignore this.
+		searchArgs.Kinds = ctagsKinds
 	}
 	baseURI, err := gituri.Parse("git://" + string(commit.repo.repo.Name) + "?" + string(commit.oid))
 	if err != nil {
-		return nil, err
+		return nil, common, err
 	}
-	symbols, err := backend.Symbols.ListTags(ctx, searchArgs)
+	start := time.Now()
+	symbols, err := listTagsPaged(ctx, searchArgs)
+	common.recordTiming(symbolSourceSymbolsService, start, len(symbols), err)
 	if baseURI == nil {
 		return
 	}
@@ -205,20 +836,278 @@ func computeSymbols(ctx context.Context, commit *GitCommitResolver, query *strin
 		}
 		resolvers = append(resolvers, resolver)
 	}
-	return resolvers, err
+	return resolvers, common, err
+}
+
+// listTagsPaged returns the first args.First symbols from the symbols service.
+// The service returns at most protocol.MaxFirst symbols per request, so larger
+// limits are fetched a page at a time (by offset) until the service returns a
+// short page. If a page fails, the symbols of the previous pages are returned
+// along with the error.
+func listTagsPaged(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+	var symbols []protocol.Symbol
+	for {
+		page := args
+		page.Offset = args.Offset + len(symbols)
+		page.First = args.First - len(symbols)
+		if page.First > protocol.MaxFirst {
+			page.First = protocol.MaxFirst
+		}
+		pageSymbols, err := listTagsWithRetry(ctx, page)
+		symbols = append(symbols, pageSymbols...)
+		if err != nil || len(pageSymbols) < page.First || len(symbols) >= args.First {
+			return symbols, err
+		}
+	}
+}
+
+// listTagsWithRetry calls the symbols service, retrying (up to the site config
+// "search.symbols.maxAttempts" times in total, with exponential backoff) when
+// it fails with a temporary error. It stops retrying when ctx is done, so
+// retries count against the symbols timeout.
+func listTagsWithRetry(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+	backoff := conf.SearchSymbolsRetryBackoff()
+	for attempt := 1; ; attempt++ {
+		symbols, err := backend.Symbols.ListTags(ctx, args)
+		if err == nil || !errcode.IsTemporary(err) || attempt >= conf.SearchSymbolsMaxAttempts() {
+			return symbols, err
+		}
+		log15.Debug("Retrying symbols service request after a temporary error.", "repo", args.Repo, "commit", args.CommitID, "attempt", attempt, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return symbols, err
+		}
+		backoff *= 2
+	}
+}
+
+// filterSymbolsByPath returns the symbols defined in the file at path or, if
+// path is a directory, anywhere beneath it. An empty path (the root of the
+// tree) matches all symbols.
+func filterSymbolsByPath(symbols []*symbolResolver, path string) []*symbolResolver {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return symbols
+	}
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if symbolInPath(symbol.uri.Fragment, path) {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
+
+// symbolInPath reports whether the file symbolPath is path itself or is
+// contained in the directory path.
+func symbolInPath(symbolPath, path string) bool {
+	symbolPath = strings.TrimPrefix(symbolPath, "/")
+	return symbolPath == path || strings.HasPrefix(symbolPath, path+"/")
+}
+
+// symbolKindSet returns the set of SymbolKind enum names in kinds, or nil if
+// kinds is empty (meaning that symbols of all kinds are allowed).
+func symbolKindSet(kinds *[]string) (map[string]struct{}, error) {
+	if kinds == nil || len(*kinds) == 0 {
+		return nil, nil
+	}
+	set := make(map[string]struct{}, len(*kinds))
+	for _, kind := range *kinds {
+		kind = strings.ToUpper(kind)
+		if !isSymbolKindName(kind) {
+			return nil, fmt.Errorf("invalid symbol kind %q", kind)
+		}
+		set[kind] = struct{}{}
+	}
+	return set, nil
+}
+
+// isSymbolKindName reports whether name is a value of the GraphQL SymbolKind
+// enum.
+func isSymbolKindName(name string) bool {
+	if name == "UNKNOWN" {
+		return true
+	}
+	for kind := lsp.SKFile; kind <= lsp.SKTypeParameter; kind++ {
+		if name == strings.ToUpper(kind.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSymbolsByKind returns the symbols whose kind is in kinds. A nil kinds
+// set matches all symbols.
+func filterSymbolsByKind(symbols []*symbolResolver, kinds map[string]struct{}) []*symbolResolver {
+	if kinds == nil {
+		return symbols
+	}
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if _, ok := kinds[symbol.Kind()]; ok {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
+
+// filterReferenceSymbols returns the symbols that are definitions, omitting
+// those whose ctags kind is one of referenceKinds (compared case-insensitively).
+func filterReferenceSymbols(symbols []*symbolResolver, referenceKinds []string) []*symbolResolver {
+	if len(referenceKinds) == 0 {
+		return symbols
+	}
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		isReference := false
+		for _, kind := range referenceKinds {
+			if strings.EqualFold(symbol.symbol.Kind, kind) {
+				isReference = true
+				break
+			}
+		}
+		if !isReference {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
+
+// symbolDeclarationKinds are the ctags kinds of symbols that are declarations
+// of symbols defined elsewhere, rather than definitions.
+var symbolDeclarationKinds = map[string]bool{
+	"prototype":   true, // C and C++ function prototypes
+	"externvar":   true, // C and C++ extern variable declarations
+	"declaration": true,
+	"subprogspec": true, // Ada subprogram specifications
+}
+
+// IsDefinition reports whether the symbol is a definition, as opposed to a declaration of a
+// symbol that is defined elsewhere (such as a C function prototype).
+func (r *symbolResolver) IsDefinition() bool {
+	return !symbolDeclarationKinds[strings.ToLower(r.symbol.Kind)]
+}
+
+// filterNestedSymbols returns the top-level symbols, which are those that
+// aren't in a container (such as a class or another function).
+func filterNestedSymbols(symbols []*symbolResolver) []*symbolResolver {
+	filtered := make([]*symbolResolver, 0, len(symbols))
+	for _, symbol := range symbols {
+		if symbol.symbol.Parent == "" {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
+
+// filterDeclarationSymbols returns the symbols that are definitions (see
+// IsDefinition).
+func filterDeclarationSymbols(symbols []*symbolResolver) []*symbolResolver {
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if symbol.IsDefinition() {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
+
+// dedupeSymbols collapses symbols with the same name, container, file, and
+// start position into one, keeping the one with the most metadata. The relative
+// order of the remaining symbols is preserved.
+func dedupeSymbols(symbols []*symbolResolver) []*symbolResolver {
+	type key struct {
+		name, container, path string
+		start                 lsp.Position
+	}
+	seen := make(map[key]int, len(symbols))
+	deduped := symbols[:0]
+	for _, symbol := range symbols {
+		k := key{
+			name:      symbol.symbol.Name,
+			container: symbol.symbol.Parent,
+			path:      symbol.uri.Fragment,
+			start:     symbol.location.lspRange.Start,
+		}
+		if i, ok := seen[k]; ok {
+			if symbolMetadataScore(symbol) > symbolMetadataScore(deduped[i]) {
+				deduped[i] = symbol
+			}
+			continue
+		}
+		seen[k] = len(deduped)
+		deduped = append(deduped, symbol)
+	}
+	return deduped
+}
+
+// symbolMetadataScore returns the number of optional fields that are known for
+// symbol. It is used to choose between otherwise identical symbols.
+func symbolMetadataScore(symbol *symbolResolver) int {
+	score := 0
+	for _, known := range []bool{
+		symbol.language != "",
+		symbol.Kind() != "UNKNOWN",
+		symbol.symbol.ParentKind != "",
+		symbol.symbol.Signature != "",
+		symbol.symbol.Pattern != "",
+	} {
+		if known {
+			score++
+		}
+	}
+	return score
+}
+
+// symbolLess reports whether symbol a sorts before symbol b in the default
+// (location) order.
+func symbolLess(a, b *symbolResolver) bool {
+	return defaultSymbolOrder.less(symbolCursorFor(a), symbolCursorFor(b))
+}
+
+// linkFileSymbols sets the fileSymbols field of each of the symbols to the
+// symbols that are defined in the same file (in the same order as in symbols),
+// and makes the symbols in the same file share the file's content. The content
+// of the first symbol in each file is kept, so linking the symbols again (after
+// filtering them) doesn't read the files again.
+func linkFileSymbols(symbols []*symbolResolver) {
+	byFile := map[string][]*symbolResolver{}
+	files := map[string]*symbolFile{}
+	for _, symbol := range symbols {
+		byFile[symbol.uri.Fragment] = append(byFile[symbol.uri.Fragment], symbol)
+		if _, ok := files[symbol.uri.Fragment]; !ok {
+			files[symbol.uri.Fragment] = symbol.file
+		}
+	}
+	for _, symbol := range symbols {
+		symbol.fileSymbols = byFile[symbol.uri.Fragment]
+		symbol.file = files[symbol.uri.Fragment]
+	}
 }
 
 func toSymbolResolver(symbol protocol.Symbol, baseURI *gituri.URI, lang string, commitResolver *GitCommitResolver) *symbolResolver {
+	if lang == "" {
+		// ctags did not report a language for this symbol, so guess it from the file name.
+		// Clients use the language to pick an icon, so a guess is better than nothing.
+		if guess, _ := inventory.GetLanguageByFilename(symbol.Path); guess != "" {
+			lang = strings.ToLower(guess)
+		}
+	}
+	// The symbols of a package or module may be reported at the path of its directory, with a
+	// trailing slash. All other paths refer to files.
+	isDir := strings.HasSuffix(symbol.Path, "/")
 	resolver := &symbolResolver{
 		symbol:   symbol,
 		language: lang,
-		uri:      baseURI.WithFilePath(symbol.Path),
+		uri:      baseURI.WithFilePath(strings.TrimSuffix(symbol.Path, "/")),
+		file:     &symbolFile{},
 	}
 	symbolRange := symbolRange(symbol)
 	resolver.location = &locationResolver{
 		resource: &GitTreeEntryResolver{
 			commit: commitResolver,
-			stat:   CreateFileInfo(resolver.uri.Fragment, false), // assume the path refers to a file (not dir)
+			stat:   CreateFileInfo(resolver.uri.Fragment, isDir),
 		},
 		lspRange: &symbolRange,
 	}
@@ -226,15 +1115,85 @@ func toSymbolResolver(symbol protocol.Symbol, baseURI *gituri.URI, lang string,
 }
 
 func (r *symbolConnectionResolver) Nodes(ctx context.Context) ([]*symbolResolver, error) {
-	symbols := r.symbols
-	if len(r.symbols) > limitOrDefault(r.first) {
-		symbols = symbols[:limitOrDefault(r.first)]
+	return r.page(), nil
+}
+
+// page returns the symbols on the requested page.
+func (r *symbolConnectionResolver) page() []*symbolResolver {
+	limit := limitOrDefault(r.first)
+	if len(r.symbols) <= limit {
+		return r.symbols
 	}
-	return symbols, nil
+	if r.fromEnd {
+		return r.symbols[len(r.symbols)-limit:]
+	}
+	return r.symbols[:limit]
 }
 
 func (r *symbolConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
-	return graphqlutil.HasNextPage(len(r.symbols) > limitOrDefault(r.first)), nil
+	page := r.page()
+	truncated := len(page) < len(r.symbols)
+	var startCursor, endCursor *string
+	if len(page) > 0 {
+		start := marshalSymbolCursor(symbolCursorFor(page[0]))
+		end := marshalSymbolCursor(symbolCursorFor(page[len(page)-1]))
+		startCursor, endCursor = &start, &end
+	}
+	return graphqlutil.NewPageInfo(
+		startCursor,
+		endCursor,
+		r.hasPreviousPage || (truncated && r.fromEnd),
+		r.hasNextPage || (truncated && !r.fromEnd),
+	), nil
+}
+
+// Errors returns the failures of symbols sources that occurred while computing the symbols. The
+// symbols are still returned (possibly incompletely) when a source fails.
+func (r *symbolConnectionResolver) Errors() []*symbolSourceError { return r.sourceErrors }
+
+func (r *symbolConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	return int32(len(r.all)), nil
+}
+
+// KindCounts returns the number of symbols of each kind in the connection, including those not on
+// the current page, ordered by decreasing count (and then by kind).
+func (r *symbolConnectionResolver) KindCounts(ctx context.Context) ([]*symbolKindCountResolver, error) {
+	counts := map[string]int32{}
+	for _, symbol := range r.all {
+		counts[symbol.Kind()]++
+	}
+	res := make([]*symbolKindCountResolver, 0, len(counts))
+	for kind, count := range counts {
+		res = append(res, &symbolKindCountResolver{kind: kind, count: count})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].count != res[j].count {
+			return res[i].count > res[j].count
+		}
+		return res[i].kind < res[j].kind
+	})
+	return res, nil
+}
+
+// KindsPresent returns the distinct kinds of the symbols in the connection, including those not on
+// the current page, in alphabetical order (e.g., to show only the applicable kind filters).
+func (r *symbolConnectionResolver) KindsPresent(ctx context.Context) ([]string, error) {
+	counts, err := r.KindCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	kinds := make([]string, len(counts))
+	for i, c := range counts {
+		kinds[i] = c.kind
+	}
+	sort.Strings(kinds)
+	return kinds, nil
+}
+
+// CountsLimitHit returns whether totalCount, kindCounts, and kindsPresent omit symbols because
+// there are more than symbolsWindowLimit of them.
+func (r *symbolConnectionResolver) CountsLimitHit(ctx context.Context) (bool, error) {
+	return r.sourceLimitHit, nil
 }
 
 type symbolResolver struct {
@@ -242,6 +1201,41 @@ type symbolResolver struct {
 	language string
 	location *locationResolver
 	uri      *gituri.URI
+
+	// fileSymbols are the symbols in the same result set that are defined in the same file as
+	// this symbol (including this symbol). It is nil if the symbol is not part of a result set
+	// (e.g., when it is a search result).
+	fileSymbols []*symbolResolver
+
+	// score is the relevance of the symbol to the query, or nil if there is no query.
+	score *int32
+
+	// nameMatchRanges are the ranges of the symbol's name that matched the query (see
+	// nameRanges), or nil if there is no query.
+	nameMatchRanges []symbolNameRange
+
+	// duplicateCount is the number of other symbols that were collapsed into this one by
+	// dedupeSymbolsByMoniker.
+	duplicateCount int
+
+	// file is the content of the file that the symbol is defined in, which is shared with
+	// fileSymbols.
+	file *symbolFile
+
+	// documentationOnce ensures that the symbol's documentation is fetched at most once.
+	documentationOnce sync.Once
+	documentation     *string
+	documentationErr  error
+
+	// referenceCountOnce ensures that the symbol's references are counted at most once.
+	referenceCountOnce sync.Once
+	referenceCount     *int32
+	referenceCountErr  error
+
+	// definitionOnce ensures that the symbol's definition is resolved at most once.
+	definitionOnce sync.Once
+	definition     LocationResolver
+	definitionErr  error
 }
 
 func (r *symbolResolver) Name() string { return r.symbol.Name }
@@ -253,18 +1247,259 @@ func (r *symbolResolver) ContainerName() *string {
 	return &r.symbol.Parent
 }
 
+// Package returns the package, namespace, or module that contains the symbol, or nil if it's
+// unknown. Unlike ContainerName, it is never a class or other type: it is only set when ctags
+// reports that the symbol's container is a package, namespace, or module (e.g., for top-level Go
+// declarations and for symbols in C++ namespaces).
+func (r *symbolResolver) Package() *string {
+	switch r.symbol.ParentKind {
+	case "package", "namespace", "module":
+		if r.symbol.Parent != "" {
+			return &r.symbol.Parent
+		}
+	}
+	return nil
+}
+
+// Children returns the symbols in the same file whose container is this
+// symbol. If the hierarchy can't be determined unambiguously (e.g., because
+// another symbol in the file has the same name), it returns no children so that
+// clients fall back to showing a flat list.
+func (r *symbolResolver) Children() []*symbolResolver {
+	qualifiedName := r.qualifiedName()
+	var children []*symbolResolver
+	for _, symbol := range r.fileSymbols {
+		if symbol == r {
+			continue
+		}
+		if symbol.symbol.Name == r.symbol.Name && symbol.symbol.Parent == r.symbol.Parent {
+			// Ambiguous: we can't tell which of the symbols with this name is the container.
+			return nil
+		}
+		if symbol.symbol.Name == r.symbol.Parent && symbol.symbol.Parent == r.symbol.Name {
+			// Skip cycles where this symbol's container claims to be contained in this symbol.
+			continue
+		}
+		if symbol.symbol.Parent == r.symbol.Name || symbol.symbol.Parent == qualifiedName {
+			children = append(children, symbol)
+		}
+	}
+	return children
+}
+
+// Detail returns details about the symbol, such as the signature of a function (e.g., "(x int)
+// error"), or nil if none are known. Only the symbols service reports details; indexed search
+// doesn't.
+func (r *symbolResolver) Detail() *string {
+	if r.symbol.Signature == "" {
+		return nil
+	}
+	return &r.symbol.Signature
+}
+
+// Documentation returns the documentation (e.g., the doc comment) of the symbol, as reported by
+// the precise code intelligence hover information at the symbol's location. It returns nil if no
+// precise code intelligence is available for the symbol's file (which is always the case for
+// symbols that ctags finds in files without LSIF data).
+func (r *symbolResolver) Documentation(ctx context.Context) (*string, error) {
+	r.documentationOnce.Do(func() {
+		r.documentation, r.documentationErr = r.fetchDocumentation(ctx)
+	})
+	return r.documentation, r.documentationErr
+}
+
+func (r *symbolResolver) fetchDocumentation(ctx context.Context) (*string, error) {
+	lsif, pos := r.lsif(ctx)
+	if lsif == nil {
+		return nil, nil
+	}
+	hover, err := lsif.Hover(ctx, pos)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, nil
+	}
+	if hover == nil || hover.Markdown() == nil {
+		return nil, nil
+	}
+	text := strings.TrimSpace(hover.Markdown().Text())
+	if text == "" {
+		return nil, nil
+	}
+	return &text, nil
+}
+
+// symbolReferencesCountLimit is the maximum number of references that
+// ReferenceCount counts.
+const symbolReferencesCountLimit = 100
+
+// symbolReferencesTimeout is the maximum time that ReferenceCount waits for the
+// references of a symbol.
+const symbolReferencesTimeout = 2 * time.Second
+
+// ReferenceCount returns the number of references to the symbol (capped at symbolReferencesCountLimit),
+// as reported by precise code intelligence. It returns nil if no precise code intelligence is
+// available for the symbol's file or if the references can't be determined quickly.
+func (r *symbolResolver) ReferenceCount(ctx context.Context) (*int32, error) {
+	r.referenceCountOnce.Do(func() {
+		r.referenceCount, r.referenceCountErr = r.fetchReferenceCount(ctx)
+	})
+	return r.referenceCount, r.referenceCountErr
+}
+
+func (r *symbolResolver) fetchReferenceCount(ctx context.Context) (*int32, error) {
+	parentCtx := ctx
+	ctx, cancel := context.WithTimeout(ctx, symbolReferencesTimeout)
+	defer cancel()
+
+	lsif, pos := r.lsif(ctx)
+	if lsif == nil {
+		return nil, nil
+	}
+	first := int32(symbolReferencesCountLimit)
+	references, err := lsif.References(ctx, &LSIFPagedQueryPositionArgs{
+		LSIFQueryPositionArgs: *pos,
+		ConnectionArgs:        graphqlutil.ConnectionArgs{First: &first},
+	})
+	if err != nil || references == nil {
+		return nil, parentCtx.Err()
+	}
+	nodes, err := references.Nodes(ctx)
+	if err != nil {
+		return nil, parentCtx.Err()
+	}
+	count := int32(len(nodes))
+	if count > symbolReferencesCountLimit {
+		count = symbolReferencesCountLimit
+	}
+	return &count, nil
+}
+
+// Definition returns the location of the symbol's definition, as reported by precise code
+// intelligence at the symbol's location. Because ctags positions can be off (e.g., the line of a
+// decorator instead of the function it decorates), this can be more precise than Location. It
+// returns Location if no precise code intelligence is available or it reports no definition.
+func (r *symbolResolver) Definition(ctx context.Context) (LocationResolver, error) {
+	r.definitionOnce.Do(func() {
+		r.definition, r.definitionErr = r.fetchDefinition(ctx)
+	})
+	return r.definition, r.definitionErr
+}
+
+func (r *symbolResolver) fetchDefinition(ctx context.Context) (LocationResolver, error) {
+	lsif, pos := r.lsif(ctx)
+	if lsif == nil {
+		return r.location, nil
+	}
+	definitions, err := lsif.Definitions(ctx, pos)
+	if err != nil || definitions == nil {
+		return r.location, ctx.Err()
+	}
+	nodes, err := definitions.Nodes(ctx)
+	if err != nil || len(nodes) == 0 || nodes[0] == nil {
+		return r.location, ctx.Err()
+	}
+	return nodes[0], nil
+}
+
+// lsif returns the precise code intelligence resolver for the symbol's file and the position of
+// the symbol in it, or a nil resolver if no precise code intelligence is available.
+func (r *symbolResolver) lsif(ctx context.Context) (LSIFQueryResolver, *LSIFQueryPositionArgs) {
+	if r.location == nil || r.location.resource == nil || r.location.lspRange == nil {
+		return nil, nil
+	}
+	lsif, err := r.location.resource.LSIF(ctx)
+	if err != nil || lsif == nil {
+		// Missing precise code intelligence is not an error: the symbol just has no precise
+		// information.
+		return nil, nil
+	}
+	return lsif, &LSIFQueryPositionArgs{
+		Line:      int32(r.location.lspRange.Start.Line),
+		Character: int32(r.location.lspRange.Start.Character),
+	}
+}
+
 func (r *symbolResolver) Kind() string /* enum SymbolKind */ {
-	kind := ctagsKindToLSPSymbolKind(r.symbol.Kind)
-	if kind == 0 {
+	kind := symbolLSPKind(r.language, r.symbol.Kind)
+	if kind < lsp.SKFile || kind > lsp.SKTypeParameter {
+		// Kinds outside the range of the SymbolKind enum (including kinds that future LSP
+		// versions may add) are reported as UNKNOWN, along with their number in KindInt.
 		return "UNKNOWN"
 	}
 	return strings.ToUpper(kind.String())
 }
 
+// KindInt returns the number of the symbol's kind in the LSP SymbolKind enumeration, or 0 if
+// the kind is unknown.
+func (r *symbolResolver) KindInt() int32 { return int32(symbolLSPKind(r.language, r.symbol.Kind)) }
+
 func (r *symbolResolver) Language() string { return r.language }
 
+// Moniker returns an identifier of the logical symbol that is the same in all repositories and
+// commits where the symbol is defined, so that clients can correlate definitions of the same
+// symbol (e.g., to find a symbol in other repositories). It is derived from the symbol's language,
+// container name, and name (e.g., "go:Server.Serve"), so it is a best-effort identifier: unrelated
+// symbols with the same qualified name in the same language have the same moniker.
+func (r *symbolResolver) Moniker() string {
+	language := r.language
+	if language == "" {
+		language = "unknown"
+	}
+	return language + ":" + r.qualifiedName()
+}
+
+// ID returns an opaque identifier of the symbol that is the same on every request for the same
+// symbol at the same commit (regardless of the revision specifier, order, or page it was listed
+// with). It is a hash of the symbol's repository, commit, path, range, and name.
+func (r *symbolResolver) ID() graphql.ID {
+	commitID := r.uri.Rev()
+	if commit := r.location.resource.commit; commit != nil {
+		commitID = string(commit.oid)
+	}
+	rng := r.location.lspRange
+	b, _ := json.Marshal([]interface{}{
+		r.uri.Repo(), commitID, r.uri.Fragment,
+		rng.Start.Line, rng.Start.Character, rng.End.Line, rng.End.Character,
+		r.symbol.Name,
+	})
+	sum := sha256.Sum256(b)
+	return graphql.ID(hex.EncodeToString(sum[:]))
+}
+
+// qualifiedName returns the symbol's name qualified by its container name, if any.
+func (r *symbolResolver) qualifiedName() string {
+	if r.symbol.Parent == "" {
+		return r.symbol.Name
+	}
+	return r.symbol.Parent + "." + r.symbol.Name
+}
+
+// languageQualifiedName returns the symbol's name qualified by its container name, if any, using
+// the container separator of the symbol's language (e.g., "Foo::Bar" in C++).
+func (r *symbolResolver) languageQualifiedName() string {
+	if r.symbol.Parent == "" {
+		return r.symbol.Name
+	}
+	return r.symbol.Parent + symbolContainerSeparator(r.language) + r.symbol.Name
+}
+
 func (r *symbolResolver) Location() *locationResolver { return r.location }
 
+// Commit returns the commit that the symbol is defined at, which is the commit of its location.
+func (r *symbolResolver) Commit() *GitCommitResolver { return r.location.resource.commit }
+
+// Line, Character, EndLine, and EndCharacter return the (zero-based) start and end of the symbol's
+// range (the same as Location.range), for clients that don't need the other location fields.
+func (r *symbolResolver) Line() int32 { return int32(r.location.lspRange.Start.Line) }
+
+func (r *symbolResolver) Character() int32 { return int32(r.location.lspRange.Start.Character) }
+
+func (r *symbolResolver) EndLine() int32 { return int32(r.location.lspRange.End.Line) }
+
+func (r *symbolResolver) EndCharacter() int32 { return int32(r.location.lspRange.End.Character) }
+
 func (r *symbolResolver) URL(ctx context.Context) (string, error) { return r.location.URL(ctx) }
 
 func (r *symbolResolver) CanonicalURL() (string, error) { return r.location.CanonicalURL() }
@@ -0,0 +1,93 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxSymbolsBodyQueryFirst is the maximum value of first when the
+	// bodyQuery argument is given, because the file that defines every symbol
+	// that is considered must be read.
+	maxSymbolsBodyQueryFirst = 100
+
+	// maxSymbolsBodyQueryFiles is the maximum number of files that are read to
+	// filter symbols by the bodyQuery argument in one request.
+	maxSymbolsBodyQueryFiles = 50
+)
+
+// newSymbolBodyQuery returns the compiled form of the bodyQuery argument. It is
+// a literal string unless regexp is true, and match modes don't apply to it.
+func newSymbolBodyQuery(args *symbolsArgs) (*symbolQuery, error) {
+	regExp := args.RegExp != nil && *args.RegExp
+	q, err := compileSymbolQuery(args.BodyQuery, &symbolsArgs{RegExp: &regExp, CaseSensitive: args.CaseSensitive})
+	if err != nil {
+		return nil, fmt.Errorf("bodyQuery: %s", err)
+	}
+	return q, nil
+}
+
+// validateSymbolsBodyQuery returns an error if the bodyQuery argument is given
+// with a first value above maxSymbolsBodyQueryFirst.
+func validateSymbolsBodyQuery(args *symbolsArgs) error {
+	if args.BodyQuery == nil || *args.BodyQuery == "" {
+		return nil
+	}
+	if limitOrDefault(args.First) > maxSymbolsBodyQueryFirst {
+		return fmt.Errorf("symbols: 'first' must be at most %d when 'bodyQuery' is given", maxSymbolsBodyQueryFirst)
+	}
+	return nil
+}
+
+// filterSymbolsByBody returns the symbols whose bodies (see symbolBody) match
+// q. The symbols in the same file share its content (see linkFileSymbols), so
+// each file is read once, and the content is kept for the snippets of the
+// returned symbols. The number of files is limited to maxSymbolsBodyQueryFiles.
+func filterSymbolsByBody(ctx context.Context, symbols []*symbolResolver, q *symbolQuery) ([]*symbolResolver, error) {
+	if q.re == nil {
+		return symbols, nil
+	}
+	linkFileSymbols(symbols)
+	// A symbol defined in each file, whose resource is used to read the file.
+	symbolByPath := map[string]*symbolResolver{}
+	for _, symbol := range symbols {
+		if strings.HasSuffix(symbol.symbol.Path, "/") {
+			continue // directories have no content
+		}
+		symbolByPath[symbol.uri.Fragment] = symbol
+	}
+	if len(symbolByPath) > maxSymbolsBodyQueryFiles {
+		return nil, fmt.Errorf("bodyQuery: the symbols are defined in more than %d files; narrow the symbols with the query or path arguments", maxSymbolsBodyQueryFiles)
+	}
+	contentByPath := make(map[string]string, len(symbolByPath))
+	for path, symbol := range symbolByPath {
+		content, err := symbol.symbolFileContent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		contentByPath[path] = content
+	}
+
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		content, ok := contentByPath[symbol.uri.Fragment]
+		if !ok {
+			continue
+		}
+		if body, ok := symbolBody(content, symbol); ok && q.re.MatchString(body) {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered, nil
+}
+
+// symbolBody returns the lines of content from the symbol's start line through
+// the last line of its definition, as reported by ctags (e.g., the closing
+// brace of a function). If the end is unknown, only the symbol's start line is
+// returned.
+func symbolBody(content string, symbol *symbolResolver) (string, bool) {
+	start := symbol.location.lspRange.Start.Line
+	end := symbol.symbol.End - 1 // ctags lines are 1-indexed
+	return symbolSnippet(content, start, end, 0)
+}
@@ -0,0 +1,91 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestComputeSymbols_bodyQuery(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "alpha", Kind: "function", Path: "a.go", Line: 3, End: 6},
+			{Name: "beta", Kind: "function", Path: "a.go", Line: 7, End: 9},
+			{Name: "gamma", Kind: "variable", Path: "a.go", Line: 10},
+			{Name: "delta", Kind: "function", Path: "b.go", Line: 1, End: 3},
+		}, nil
+	}
+	reads := map[string]int{}
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		reads[name]++
+		switch name {
+		case "a.go":
+			return []byte("package a\n\nfunc alpha() {\n\t// TODO: fix\n\treturn\n}\nfunc beta() {\n\treturn\n}\nvar gamma = 1 // todo\n"), nil
+		default:
+			return []byte("func delta() {\n\t// Done.\n}\n"), nil
+		}
+	}
+	defer func() { git.Mocks.ReadFile = nil }()
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		bodyQuery     string
+		regExp        bool
+		caseSensitive bool
+		want          []string
+	}{
+		{bodyQuery: "todo", want: []string{"alpha", "gamma"}},
+		{bodyQuery: "TODO", caseSensitive: true, want: []string{"alpha"}},
+		{bodyQuery: `fix|done\.`, regExp: true, want: []string{"alpha", "delta"}},
+		// The body query is a literal string unless regexp is true.
+		{bodyQuery: `fix|done\.`, want: []string{}},
+	}
+	for _, test := range tests {
+		reads = map[string]int{}
+		bodyQuery, regExp, caseSensitive := test.bodyQuery, test.regExp, test.caseSensitive
+		symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{BodyQuery: &bodyQuery, RegExp: &regExp, CaseSensitive: &caseSensitive})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := symbolNames(symbols); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.bodyQuery, got, test.want)
+		}
+		// The content read to match the bodies is shared with the snippets of the symbols.
+		for _, symbol := range symbols {
+			if _, err := symbol.Snippet(context.Background(), &struct{ ContextLines *int32 }{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if want := map[string]int{"a.go": 1, "b.go": 1}; !reflect.DeepEqual(reads, want) {
+			t.Errorf("%q: got reads %v, want %v", test.bodyQuery, reads, want)
+		}
+	}
+}
+
+func TestSymbolConnection_bodyQueryFirst(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	bodyQuery, first := "TODO", int32(maxSymbolsBodyQueryFirst+1)
+	if _, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, BodyQuery: &bodyQuery}); err == nil {
+		t.Error("got nil error for a first value above the bodyQuery limit")
+	}
+}
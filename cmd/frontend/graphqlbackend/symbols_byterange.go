@@ -0,0 +1,84 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// symbolFile is the content of a file that symbols are defined in. It is
+// shared by the symbols of a result set that are defined in the same file (see
+// linkFileSymbols), so that the file is read at most once.
+type symbolFile struct {
+	once    sync.Once
+	content string
+	err     error
+}
+
+// symbolFileContent returns the content of the file that the symbol is
+// defined in.
+func (r *symbolResolver) symbolFileContent(ctx context.Context) (string, error) {
+	file := r.file
+	file.once.Do(func() {
+		file.content, file.err = r.location.resource.Content(ctx)
+	})
+	return file.content, file.err
+}
+
+// ByteRange returns the range of the symbol's name in its file as byte offsets
+// from the start of the file, or nil if the name can't be found on the
+// symbol's line (e.g., because ctags reported an inexact position).
+func (r *symbolResolver) ByteRange(ctx context.Context) (*symbolByteRangeResolver, error) {
+	content, err := r.symbolFileContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start, end, ok := symbolByteRange(content, r.location.lspRange.Start.Line, r.location.lspRange.Start.Character, r.symbol.Name)
+	if !ok {
+		return nil, nil
+	}
+	return &symbolByteRangeResolver{start: int32(start), end: int32(end)}, nil
+}
+
+// symbolByteRange returns the byte offsets in content of the start and end of
+// the symbol name on the (zero-based) line.
+//
+// The symbol's character is the byte offset of the name in the line that
+// ctags guessed from its search pattern (see ctagsSymbolCharacter), which can
+// be off if the pattern contains escape sequences. So the name is looked up
+// in the line, preferring an occurrence at the character.
+func symbolByteRange(content string, line, character int, name string) (start, end int, ok bool) {
+	if name == "" {
+		return 0, 0, false
+	}
+	lineStart := 0
+	for i := 0; i < line; i++ {
+		n := strings.IndexByte(content[lineStart:], '\n')
+		if n < 0 {
+			return 0, 0, false
+		}
+		lineStart += n + 1
+	}
+	text := content[lineStart:]
+	if n := strings.IndexByte(text, '\n'); n >= 0 {
+		text = text[:n]
+	}
+
+	i := character
+	if i < 0 || i > len(text) || !strings.HasPrefix(text[i:], name) {
+		if i = strings.Index(text, name); i < 0 {
+			return 0, 0, false
+		}
+	}
+	return lineStart + i, lineStart + i + len(name), true
+}
+
+// symbolByteRangeResolver is a range in a file, as byte offsets from the start
+// of the file.
+type symbolByteRangeResolver struct {
+	start, end int32
+}
+
+func (r *symbolByteRangeResolver) Start() int32 { return r.start }
+
+func (r *symbolByteRangeResolver) End() int32 { return r.end }
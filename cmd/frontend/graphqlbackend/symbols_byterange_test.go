@@ -0,0 +1,85 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestSymbolByteRange(t *testing.T) {
+	content := "package a\n\n// héllo wörld\nfunc héllo() {}\nx := `\\/` + y\n"
+	tests := []struct {
+		name            string
+		line, character int
+		symbol          string
+		wantStart       int
+		wantOK          bool
+	}{
+		{name: "first line", line: 0, character: 8, symbol: "a", wantStart: 8, wantOK: true},
+		// The previous line contains multibyte characters (and the name), so the name's byte
+		// offset in the file differs from its character offset.
+		{name: "multibyte", line: 3, character: 5, symbol: "héllo", wantStart: 33, wantOK: true},
+		// ctags guessed the character from an escaped pattern, so it is off.
+		{name: "inexact character", line: 4, character: 13, symbol: "y", wantStart: 57, wantOK: true},
+		{name: "not on line", line: 1, character: 0, symbol: "a", wantOK: false},
+		{name: "line out of range", line: 9, character: 0, symbol: "a", wantOK: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, end, ok := symbolByteRange(content, test.line, test.character, test.symbol)
+			if ok != test.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, test.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if start != test.wantStart || end != test.wantStart+len(test.symbol) {
+				t.Errorf("got [%d, %d), want [%d, %d)", start, end, test.wantStart, test.wantStart+len(test.symbol))
+			}
+			if got := content[start:end]; got != test.symbol {
+				t.Errorf("got range of %q, want %q", got, test.symbol)
+			}
+		})
+	}
+}
+
+func TestSymbolResolver_ByteRange(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	reads := 0
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		reads++
+		return []byte("package a\n\nfunc alpha() {}\nfunc beta() {}\n"), nil
+	}
+	defer func() { git.Mocks.ReadFile = nil }()
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	symbols := []*symbolResolver{
+		toSymbolResolver(protocol.Symbol{Name: "alpha", Path: "a.go", Line: 3, Pattern: "/^func alpha() {}$/"}, baseURI, "go", commit),
+		toSymbolResolver(protocol.Symbol{Name: "beta", Path: "a.go", Line: 4, Pattern: "/^func beta() {}$/"}, baseURI, "go", commit),
+	}
+	linkFileSymbols(symbols)
+
+	for i, want := range []struct{ start, end int32 }{{16, 21}, {32, 36}} {
+		r, err := symbols[i].ByteRange(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r == nil || r.Start() != want.start || r.End() != want.end {
+			t.Errorf("%s: got %+v, want [%d, %d)", symbols[i].Name(), r, want.start, want.end)
+		}
+	}
+	if reads != 1 {
+		t.Errorf("got %d file reads, want 1", reads)
+	}
+}
@@ -0,0 +1,172 @@
+package graphqlbackend
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// symbolsCache caches the results of computeSymbols for the TTL given by the
+// site config "search.symbols.cacheTTL", so that repeated identical requests
+// (e.g., from multiple users viewing the same file) don't recompute the
+// symbols. Entries are keyed by symbolsCacheKey, and at most the site config
+// "search.symbols.cacheSize" entries are kept.
+//
+// Keys include the resolved commit, so when a repository's index moves to a
+// new commit, the entries for the old commit are no longer requested and are
+// the first to be evicted.
+var (
+	symbolsCacheMu sync.Mutex
+	symbolsCache   = lru.New(500)
+)
+
+var symbolsCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "src",
+	Subsystem: "graphql",
+	Name:      "symbols_cache_requests_total",
+	Help:      "Number of lookups in the cache of computed symbol lists, by whether they hit or missed.",
+}, []string{"result"})
+
+// symbolsCacheKey returns the cache key for the symbols of commit at path
+// computed with args.
+//
+// The key includes all of args (by way of its JSON encoding, so any new
+// argument is automatically a part of the key) and any site configuration
+// that affects the result.
+func symbolsCacheKey(commit *GitCommitResolver, path string, args *symbolsArgs) (string, error) {
+	// The top-level filter is applied to cached symbols (see computeSymbols), so the symbols
+	// cached with and without it are the same.
+	keyArgs := *args
+	keyArgs.TopLevelOnly = nil
+	key := struct {
+		Repo              string
+		Commit            GitObjectID
+		Path              string
+		Args              *symbolsArgs
+		Exact             bool                         `json:",omitempty"`
+		GeneratedPatterns []string                     `json:",omitempty"`
+		NoisePatterns     map[string][]string          `json:",omitempty"`
+		ReferenceKinds    []string                     `json:",omitempty"`
+		KindOverrides     map[string]map[string]string `json:",omitempty"`
+		WordBoundaries    map[string]string            `json:",omitempty"`
+		MinQueryLength    schema.SearchSymbolsMinQueryLength
+	}{
+		Repo:           string(commit.repo.repo.Name),
+		Commit:         commit.oid,
+		Path:           strings.Trim(path, "/"),
+		Args:           &keyArgs,
+		Exact:          args.exact,
+		NoisePatterns:  conf.SearchSymbolsNoisePatterns(),
+		KindOverrides:  conf.SearchSymbolsKindOverrides(),
+		MinQueryLength: conf.SearchSymbolsMinQueryLength(),
+	}
+	if args.ExcludeGenerated != nil && *args.ExcludeGenerated {
+		key.GeneratedPatterns = conf.SearchSymbolsGeneratedPatterns()
+	}
+	if args.Match != nil && strings.ToUpper(*args.Match) == symbolMatchWholeWord {
+		key.WordBoundaries = conf.SearchSymbolsWordBoundaries()
+	}
+	if args.DefinitionsOnly == nil || *args.DefinitionsOnly {
+		key.ReferenceKinds = conf.SearchSymbolsReferenceKinds()
+	}
+	b, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// symbolsCacheEntry is a cached result of computeSymbols. It stores the symbols
+// instead of their resolvers because resolvers have per-request state (such as
+// the commit's input revspec and lazily fetched fields).
+type symbolsCacheEntry struct {
+	expires   time.Time
+	symbols   []protocol.Symbol
+	languages []string
+	scores    []*int32
+	// duplicateCounts are the symbols' duplicateCount (see dedupeSymbolsByMoniker).
+	duplicateCounts []int
+	nameMatchRanges [][]symbolNameRange
+	common          symbolsCommon
+}
+
+func newSymbolsCacheEntry(res []*symbolResolver, common symbolsCommon, ttl time.Duration) *symbolsCacheEntry {
+	entry := &symbolsCacheEntry{
+		expires:         time.Now().Add(ttl),
+		symbols:         make([]protocol.Symbol, len(res)),
+		languages:       make([]string, len(res)),
+		scores:          make([]*int32, len(res)),
+		duplicateCounts: make([]int, len(res)),
+		nameMatchRanges: make([][]symbolNameRange, len(res)),
+		common:          common,
+	}
+	for i, r := range res {
+		entry.symbols[i] = r.symbol
+		entry.languages[i] = r.language
+		entry.scores[i] = r.score
+		entry.duplicateCounts[i] = r.duplicateCount
+		entry.nameMatchRanges[i] = r.nameMatchRanges
+	}
+	return entry
+}
+
+// resolvers returns new resolvers for the cached symbols in commit.
+func (e *symbolsCacheEntry) resolvers(commit *GitCommitResolver) []*symbolResolver {
+	baseURI, err := gituri.Parse("git://" + string(commit.repo.repo.Name) + "?" + string(commit.oid))
+	if err != nil {
+		return nil
+	}
+	res := make([]*symbolResolver, 0, len(e.symbols))
+	for i, symbol := range e.symbols {
+		r := toSymbolResolver(symbol, baseURI, e.languages[i], commit)
+		r.score = e.scores[i]
+		r.duplicateCount = e.duplicateCounts[i]
+		r.nameMatchRanges = e.nameMatchRanges[i]
+		res = append(res, r)
+	}
+	linkFileSymbols(res)
+	return res
+}
+
+func getSymbolsCacheEntry(key string) (entry *symbolsCacheEntry, ok bool) {
+	defer func() {
+		if ok {
+			symbolsCacheRequests.WithLabelValues("hit").Inc()
+		} else {
+			symbolsCacheRequests.WithLabelValues("miss").Inc()
+		}
+	}()
+	symbolsCacheMu.Lock()
+	defer symbolsCacheMu.Unlock()
+	v, ok := symbolsCache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	entry = v.(*symbolsCacheEntry)
+	if time.Now().After(entry.expires) {
+		symbolsCache.Remove(key)
+		return nil, false
+	}
+	return entry, true
+}
+
+func addSymbolsCacheEntry(key string, entry *symbolsCacheEntry) {
+	symbolsCacheMu.Lock()
+	defer symbolsCacheMu.Unlock()
+	// Apply changes to the site config "search.symbols.cacheSize", evicting
+	// the least recently used entries if it shrank.
+	symbolsCache.MaxEntries = conf.SearchSymbolsCacheSize()
+	for symbolsCache.Len() > symbolsCache.MaxEntries {
+		symbolsCache.RemoveOldest()
+	}
+	symbolsCache.Add(key, entry)
+}
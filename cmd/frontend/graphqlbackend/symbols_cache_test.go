@@ -0,0 +1,94 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestComputeSymbols_cache(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	calls := 0
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		calls++
+		return []protocol.Symbol{
+			{Name: "a", Path: "a.go", Line: 1, Kind: "function"},
+			{Name: "b", Path: "a.go", Line: 2, Kind: "class"},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	compute := func(args *symbolsArgs) []string {
+		t.Helper()
+		symbols, _, err := computeSymbols(context.Background(), commit, "", args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return symbolNames(symbols)
+	}
+
+	first := compute(&symbolsArgs{})
+	if got := compute(&symbolsArgs{}); !reflect.DeepEqual(got, first) {
+		t.Errorf("cached: got %v, want %v", got, first)
+	}
+	if calls != 1 {
+		t.Errorf("got %d ListTags calls for identical requests, want 1", calls)
+	}
+
+	kinds := []string{"CLASS"}
+	if got, want := compute(&symbolsArgs{Kinds: &kinds}), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("different filters: got %v, want %v", got, want)
+	}
+	if calls != 2 {
+		t.Errorf("got %d ListTags calls after changing the filters, want 2", calls)
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchSymbolsCacheTTL: "0s"}})
+	defer conf.Mock(nil)
+	compute(&symbolsArgs{})
+	if calls != 3 {
+		t.Errorf("got %d ListTags calls with the cache disabled, want 3", calls)
+	}
+}
+
+func TestComputeSymbols_cacheSize(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchSymbolsCacheSize: 1}})
+	defer conf.Mock(nil)
+
+	calls := 0
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		calls++
+		return []protocol.Symbol{{Name: "a", Path: "a.go", Line: 1}}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	for _, path := range []string{"a.go", "b.go", "a.go"} {
+		if _, _, err := computeSymbols(context.Background(), commit, path, &symbolsArgs{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Only one list fits in the cache, so the list for a.go was evicted.
+	if calls != 3 {
+		t.Errorf("got %d ListTags calls, want 3", calls)
+	}
+	if symbolsCache.Len() != 1 {
+		t.Errorf("got %d cache entries, want 1", symbolsCache.Len())
+	}
+}
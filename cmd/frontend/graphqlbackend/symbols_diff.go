@@ -0,0 +1,222 @@
+package graphqlbackend
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// The values of the GraphQL SymbolChangeKind enum.
+const (
+	symbolChangeAdded    = "ADDED"
+	symbolChangeRemoved  = "REMOVED"
+	symbolChangeModified = "MODIFIED"
+)
+
+// maxSymbolChangesFiles is the maximum number of changed files whose symbols
+// are compared by RepositoryComparison.symbolChanges.
+const maxSymbolChangesFiles = 500
+
+// SymbolChanges returns the symbols that were added, removed, or modified in the files changed
+// between the base and head of the comparison.
+func (r *RepositoryComparisonResolver) SymbolChanges(ctx context.Context, args *graphqlutil.ConnectionArgs) (*symbolChangeConnectionResolver, error) {
+	if err := validateSymbolsFirst(args.First); err != nil {
+		return nil, err
+	}
+	fileDiffs, err := (&fileDiffConnectionResolver{cmp: r}).compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	paths := changedPaths(fileDiffs)
+	res := &symbolChangeConnectionResolver{}
+	if len(paths) > maxSymbolChangesFiles {
+		paths = paths[:maxSymbolChangesFiles]
+		res.limitHit = true
+	}
+	base, err := r.symbolChangesBase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := res.diffSymbolsInFiles(ctx, base, r.head, paths); err != nil {
+		return nil, err
+	}
+	if limit := limitOrDefault(args.First); len(res.changes) > limit {
+		res.changes = res.changes[:limit]
+		res.limitHit = true
+	}
+	return res, nil
+}
+
+// symbolChangesBase returns the commit to compare the head's symbols with: the
+// merge base of the base and head, because the file diffs are between the
+// merge base and the head (base...head). It is nil if the base is the empty
+// tree.
+func (r *RepositoryComparisonResolver) symbolChangesBase(ctx context.Context) (*GitCommitResolver, error) {
+	if r.base == nil {
+		return nil, nil
+	}
+	mergeBase, err := git.MergeBase(ctx, gitserver.Repo{Name: r.repo.repo.Name}, api.CommitID(r.base.OID()), api.CommitID(r.head.OID()))
+	if err != nil {
+		return nil, err
+	}
+	if mergeBase == api.CommitID(r.base.OID()) {
+		return r.base, nil
+	}
+	return &GitCommitResolver{repo: r.repo, oid: GitObjectID(mergeBase)}, nil
+}
+
+// diffSymbolsInFiles sets the changes to the symbols in the files with the
+// given paths between the base and head commits. If either commit has more
+// symbols in the files than symbolsCountLimit, some of their symbols would be
+// missing from one side and reported as added or removed, so fewer files are
+// compared instead and limitHit is set.
+func (r *symbolChangeConnectionResolver) diffSymbolsInFiles(ctx context.Context, base, head *GitCommitResolver, paths []string) error {
+	for len(paths) > 0 {
+		baseSymbols, baseCommon, err := symbolsInFiles(ctx, base, paths)
+		if err != nil {
+			return err
+		}
+		headSymbols, headCommon, err := symbolsInFiles(ctx, head, paths)
+		if err != nil {
+			return err
+		}
+		if baseCommon.limitHit || headCommon.limitHit {
+			r.limitHit = true
+			paths = paths[:len(paths)/2]
+			continue
+		}
+		r.sourceErrors = append(baseCommon.sourceErrors, headCommon.sourceErrors...)
+		r.changes = diffSymbols(baseSymbols, headSymbols)
+		break
+	}
+	return nil
+}
+
+// symbolsInFiles returns the symbols (up to symbolsCountLimit) in the files
+// with the given paths at the commit. Only computing the symbols in the
+// changed files makes the symbols limit apply to them instead of the whole
+// repository. A nil commit is the empty tree, which has no symbols.
+func symbolsInFiles(ctx context.Context, commit *GitCommitResolver, paths []string) ([]*symbolResolver, symbolsCommon, error) {
+	if commit == nil {
+		return nil, symbolsCommon{}, nil
+	}
+	quoted := make([]string, len(paths))
+	for i, p := range paths {
+		quoted[i] = regexp.QuoteMeta(p)
+	}
+	include := []string{"^(?:" + strings.Join(quoted, "|") + ")$"}
+	first := int32(symbolsCountLimit)
+	return computeSymbols(ctx, commit, "", &symbolsArgs{
+		ConnectionArgs:  graphqlutil.ConnectionArgs{First: &first},
+		IncludePatterns: &include,
+	})
+}
+
+// changedPaths returns the (sorted and unique) paths of the files changed in
+// the file diffs, including both the old and new paths of renamed files.
+func changedPaths(fileDiffs []*diff.FileDiff) []string {
+	seen := map[string]bool{}
+	var paths []string
+	for _, fileDiff := range fileDiffs {
+		for _, name := range []string{fileDiff.OrigName, fileDiff.NewName} {
+			if name == "" || name == "/dev/null" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			paths = append(paths, name)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// symbolChangeKey identifies a symbol across the base and head of a
+// comparison. It deliberately omits the symbol's position, so that a symbol
+// that moved within its file (e.g., because lines were added above it) is not
+// reported as changed.
+type symbolChangeKey struct {
+	name, containerName, path string
+}
+
+func symbolChangeKeyFor(symbol *symbolResolver) symbolChangeKey {
+	return symbolChangeKey{name: symbol.symbol.Name, containerName: symbol.symbol.Parent, path: symbol.uri.Fragment}
+}
+
+// diffSymbols returns the changes from the base symbols to the head symbols
+// (which must each be sorted by location), ordered by the location of the
+// symbol in the head (or, for removed symbols, the base). Symbols are matched
+// by name, container name, and file. Matched symbols are modified if their kind
+// or signature changed. If multiple symbols have the same key (e.g., overloaded
+// functions), they are matched in order.
+func diffSymbols(base, head []*symbolResolver) []*symbolChangeResolver {
+	unmatched := map[symbolChangeKey][]*symbolResolver{}
+	for _, symbol := range base {
+		key := symbolChangeKeyFor(symbol)
+		unmatched[key] = append(unmatched[key], symbol)
+	}
+
+	var changes []*symbolChangeResolver
+	for _, symbol := range head {
+		key := symbolChangeKeyFor(symbol)
+		if candidates := unmatched[key]; len(candidates) > 0 {
+			old := candidates[0]
+			unmatched[key] = candidates[1:]
+			if old.symbol.Kind != symbol.symbol.Kind || old.symbol.Signature != symbol.symbol.Signature {
+				changes = append(changes, &symbolChangeResolver{kind: symbolChangeModified, base: old, head: symbol})
+			}
+			continue
+		}
+		changes = append(changes, &symbolChangeResolver{kind: symbolChangeAdded, head: symbol})
+	}
+	for _, symbol := range base {
+		key := symbolChangeKeyFor(symbol)
+		if candidates := unmatched[key]; len(candidates) > 0 && candidates[0] == symbol {
+			unmatched[key] = candidates[1:]
+			changes = append(changes, &symbolChangeResolver{kind: symbolChangeRemoved, base: symbol})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return symbolLess(changes[i].symbol(), changes[j].symbol())
+	})
+	return changes
+}
+
+type symbolChangeConnectionResolver struct {
+	changes      []*symbolChangeResolver
+	limitHit     bool
+	sourceErrors []*symbolSourceError
+}
+
+func (r *symbolChangeConnectionResolver) Nodes() []*symbolChangeResolver { return r.changes }
+
+func (r *symbolChangeConnectionResolver) LimitHit() bool { return r.limitHit }
+
+func (r *symbolChangeConnectionResolver) Errors() []*symbolSourceError { return r.sourceErrors }
+
+// symbolChangeResolver is a symbol that was added, removed, or modified
+// between the base and head of a comparison.
+type symbolChangeResolver struct {
+	kind       string // a SymbolChangeKind enum value
+	base, head *symbolResolver
+}
+
+func (r *symbolChangeResolver) Kind() string { return r.kind }
+
+func (r *symbolChangeResolver) Base() *symbolResolver { return r.base }
+
+func (r *symbolChangeResolver) Head() *symbolResolver { return r.head }
+
+// symbol returns the symbol in the head, or in the base if it was removed.
+func (r *symbolChangeResolver) symbol() *symbolResolver {
+	if r.head != nil {
+		return r.head
+	}
+	return r.base
+}
@@ -0,0 +1,127 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestDiffSymbols(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	symbols := func(tags ...protocol.Symbol) []*symbolResolver {
+		var res []*symbolResolver
+		for _, tag := range tags {
+			res = append(res, toSymbolResolver(tag, baseURI, "go", nil))
+		}
+		return res
+	}
+	base := symbols(
+		protocol.Symbol{Name: "f", Kind: "func", Signature: "()", Path: "a.go", Line: 1},
+		protocol.Symbol{Name: "g", Kind: "func", Signature: "()", Path: "a.go", Line: 5},
+		protocol.Symbol{Name: "h", Kind: "func", Signature: "()", Path: "a.go", Line: 9},
+		protocol.Symbol{Name: "x", Kind: "var", Path: "b.go", Line: 1},
+	)
+	head := symbols(
+		protocol.Symbol{Name: "e", Kind: "func", Signature: "()", Path: "a.go", Line: 1},
+		protocol.Symbol{Name: "f", Kind: "func", Signature: "()", Path: "a.go", Line: 3},
+		protocol.Symbol{Name: "h", Kind: "func", Signature: "(x int)", Path: "a.go", Line: 11},
+		protocol.Symbol{Name: "x", Kind: "var", Path: "b.go", Line: 1},
+	)
+	var got []string
+	for _, change := range diffSymbols(base, head) {
+		got = append(got, change.Kind()+" "+change.symbol().Name())
+	}
+	if want := []string{"ADDED e", "REMOVED g", "MODIFIED h"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDiffSymbolsInFiles_limitHit(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	// z.go has more symbols than symbolsCountLimit at the base, so comparing it would report most
+	// of them as removed even though they might be in the part of the base that was cut off.
+	symbolsAt := map[api.CommitID][]protocol.Symbol{
+		"c1": {{Name: "f", Kind: "function", Path: "b.go", Line: 1}},
+		"c2": {{Name: "f", Kind: "function", Path: "b.go", Line: 1}, {Name: "g", Kind: "function", Path: "b.go", Line: 2}},
+	}
+	for i := 0; i <= symbolsCountLimit; i++ {
+		symbolsAt["c1"] = append(symbolsAt["c1"], protocol.Symbol{Name: fmt.Sprintf("s%d", i), Kind: "function", Path: "z.go", Line: i + 1})
+	}
+	symbolsAt["c2"] = append(symbolsAt["c2"], protocol.Symbol{Name: "s0", Kind: "function", Path: "z.go", Line: 1})
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		include := regexp.MustCompile(args.IncludePatterns[0])
+		var symbols []protocol.Symbol
+		for _, symbol := range symbolsAt[args.CommitID] {
+			if include.MatchString(symbol.Path) {
+				symbols = append(symbols, symbol)
+			}
+		}
+		return mockPagedListTags(symbols)(ctx, args)
+	}
+
+	repo := &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}
+	res := &symbolChangeConnectionResolver{}
+	if err := res.diffSymbolsInFiles(context.Background(), &GitCommitResolver{repo: repo, oid: "c1"}, &GitCommitResolver{repo: repo, oid: "c2"}, []string{"b.go", "z.go"}); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, change := range res.changes {
+		got = append(got, change.Kind()+" "+change.symbol().Name())
+	}
+	if want := []string{"ADDED g"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !res.limitHit {
+		t.Error("got limitHit false, want true")
+	}
+}
+
+func TestRepositoryComparisonResolver_symbolChangesBase(t *testing.T) {
+	defer git.ResetMocks()
+	git.Mocks.MergeBase = func(a, b api.CommitID) (api.CommitID, error) {
+		if a != "c1" || b != "c2" {
+			t.Errorf("got merge base of %q and %q, want of c1 and c2", a, b)
+		}
+		return "c0", nil
+	}
+
+	repo := &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}
+	cmp := &RepositoryComparisonResolver{repo: repo, base: &GitCommitResolver{repo: repo, oid: "c1"}, head: &GitCommitResolver{repo: repo, oid: "c2"}}
+	base, err := cmp.symbolChangesBase(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.OID() != "c0" {
+		t.Errorf("got base %q, want the merge base c0", base.OID())
+	}
+
+	cmp.base = nil
+	if base, err := cmp.symbolChangesBase(context.Background()); base != nil || err != nil {
+		t.Errorf("with the empty tree as the base: got %v, %v, want nil, nil", base, err)
+	}
+}
+
+func TestChangedPaths(t *testing.T) {
+	got := changedPaths([]*diff.FileDiff{
+		{OrigName: "/dev/null", NewName: "b.go"},
+		{OrigName: "a.go", NewName: "a.go"},
+		{OrigName: "old.go", NewName: "new.go"},
+		{OrigName: "c.go", NewName: "/dev/null"},
+	})
+	if want := []string{"a.go", "b.go", "c.go", "new.go", "old.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
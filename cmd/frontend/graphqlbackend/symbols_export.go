@@ -0,0 +1,111 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+)
+
+// maxSymbolExportPageSize is the maximum number of symbols on a page of
+// allSymbols. It is less than the maximum number of symbols that the symbols
+// service returns, so that the extra symbol that detects the next page fits.
+const maxSymbolExportPageSize = 250
+
+const symbolExportCursorKind = "SymbolExportCursor"
+
+type allSymbolsArgs struct {
+	First *int32
+	After *string
+}
+
+// AllSymbols pages through all of the symbols defined as of the commit, for
+// tools that build external symbol indexes. Unlike symbols, it is not limited
+// to a window of the first symbols: each page is fetched from the symbols
+// service on its own (by offset, in the order the service stores them), so no
+// more than a page of symbols is held in memory. The symbols are not filtered,
+// deduplicated, or sorted.
+//
+// Only site admins may use it, because exporting a large repository makes the
+// symbols service do a lot of work.
+func (r *GitCommitResolver) AllSymbols(ctx context.Context, args *allSymbolsArgs) (*symbolExportConnectionResolver, error) {
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	first := maxSymbolExportPageSize
+	if args.First != nil {
+		if *args.First < 0 || *args.First > maxSymbolExportPageSize {
+			return nil, fmt.Errorf("allSymbols: 'first' must be between 0 and %d", maxSymbolExportPageSize)
+		}
+		first = int(*args.First)
+	}
+	offset := 0
+	if args.After != nil {
+		if kind := relay.UnmarshalKind(graphql.ID(*args.After)); kind != symbolExportCursorKind {
+			return nil, fmt.Errorf("cannot unmarshal symbol export cursor type: %q", kind)
+		}
+		if err := relay.UnmarshalSpec(graphql.ID(*args.After), &offset); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := symbolsComputeLimiter.acquire(); err != nil {
+		return nil, err
+	}
+	defer symbolsComputeLimiter.release()
+	ctx, done := context.WithTimeout(ctx, conf.SearchSymbolsTimeout())
+	defer done()
+
+	symbols, err := listTagsWithRetry(ctx, search.SymbolsParameters{
+		Repo:     r.repo.repo.Name,
+		CommitID: api.CommitID(r.oid),
+		Offset:   offset,
+		First:    first + 1, // add 1 so we can determine PageInfo.hasNextPage
+	})
+	if err != nil {
+		return nil, err
+	}
+	hasNextPage := len(symbols) > first
+	if hasNextPage {
+		symbols = symbols[:first]
+	}
+
+	baseURI, err := gituri.Parse("git://" + string(r.repo.repo.Name) + "?" + string(r.oid))
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*symbolResolver, 0, len(symbols))
+	for _, symbol := range symbols {
+		resolvers = append(resolvers, toSymbolResolver(symbol, baseURI, strings.ToLower(symbol.Language), r))
+	}
+	linkFileSymbols(resolvers)
+	return &symbolExportConnectionResolver{
+		symbols:     resolvers,
+		endOffset:   offset + len(symbols),
+		hasNextPage: hasNextPage,
+	}, nil
+}
+
+// symbolExportConnectionResolver is a page of allSymbols.
+type symbolExportConnectionResolver struct {
+	symbols     []*symbolResolver
+	endOffset   int // the offset of the first symbol on the next page
+	hasNextPage bool
+}
+
+func (r *symbolExportConnectionResolver) Nodes() []*symbolResolver { return r.symbols }
+
+func (r *symbolExportConnectionResolver) PageInfo() *graphqlutil.PageInfo {
+	if !r.hasNextPage {
+		return graphqlutil.HasNextPage(false)
+	}
+	return graphqlutil.NextPageCursor(string(relay.MarshalID(symbolExportCursorKind, r.endOffset)))
+}
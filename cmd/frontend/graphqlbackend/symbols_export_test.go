@@ -0,0 +1,73 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+)
+
+func TestGitCommitResolver_AllSymbols(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var all []protocol.Symbol
+	for i := 0; i < 5; i++ {
+		all = append(all, protocol.Symbol{Name: fmt.Sprintf("s%d", i), Path: "a.go", Line: i + 1})
+	}
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		end := args.Offset + args.First
+		if end > len(all) {
+			end = len(all)
+		}
+		return all[args.Offset:end], nil
+	}
+	siteAdmin := false
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: siteAdmin}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	if _, err := commit.AllSymbols(ctx, &allSymbolsArgs{}); err != backend.ErrMustBeSiteAdmin {
+		t.Errorf("non-admin: got error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+	}
+
+	siteAdmin = true
+	first := int32(2)
+	var pages [][]string
+	var after *string
+	for {
+		conn, err := commit.AllSymbols(ctx, &allSymbolsArgs{First: &first, After: after})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, symbolNames(conn.Nodes()))
+		if !conn.PageInfo().HasNextPage() {
+			break
+		}
+		after = conn.PageInfo().EndCursor()
+	}
+	if want := [][]string{{"s0", "s1"}, {"s2", "s3"}, {"s4"}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("got pages %v, want %v", pages, want)
+	}
+
+	tooMany := int32(maxSymbolExportPageSize + 1)
+	if _, err := commit.AllSymbols(ctx, &allSymbolsArgs{First: &tooMany}); err == nil {
+		t.Error("got nil error for a page size over the maximum")
+	}
+	cursor := marshalSymbolCursor(&symbolCursor{Path: "a.go"})
+	if _, err := commit.AllSymbols(ctx, &allSymbolsArgs{After: &cursor}); err == nil {
+		t.Error("got nil error for a symbols cursor")
+	}
+}
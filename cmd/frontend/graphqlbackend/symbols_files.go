@@ -0,0 +1,42 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+)
+
+// NodesByFile returns the symbols on the current page (see Nodes) grouped by
+// the file they are defined in. The files are ordered by path, and the symbols
+// in each file by location, regardless of the orderBy argument.
+func (r *symbolConnectionResolver) NodesByFile(ctx context.Context) ([]*symbolFileGroupResolver, error) {
+	return groupSymbolsByFile(r.page()), nil
+}
+
+// groupSymbolsByFile groups the symbols by the file they are defined in.
+func groupSymbolsByFile(symbols []*symbolResolver) []*symbolFileGroupResolver {
+	sorted := make([]*symbolResolver, len(symbols))
+	copy(sorted, symbols)
+	// The location order sorts by path first, so the symbols of each file are contiguous.
+	sort.SliceStable(sorted, func(i, j int) bool { return symbolLess(sorted[i], sorted[j]) })
+
+	var groups []*symbolFileGroupResolver
+	for _, symbol := range sorted {
+		if len(groups) == 0 || groups[len(groups)-1].path != symbol.uri.Fragment {
+			groups = append(groups, &symbolFileGroupResolver{path: symbol.uri.Fragment})
+		}
+		group := groups[len(groups)-1]
+		group.symbols = append(group.symbols, symbol)
+	}
+	return groups
+}
+
+// symbolFileGroupResolver is the symbols of a symbol connection that are
+// defined in the same file.
+type symbolFileGroupResolver struct {
+	path    string
+	symbols []*symbolResolver
+}
+
+func (r *symbolFileGroupResolver) Path() string { return r.path }
+
+func (r *symbolFileGroupResolver) Symbols() []*symbolResolver { return r.symbols }
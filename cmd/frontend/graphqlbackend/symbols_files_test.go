@@ -0,0 +1,58 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+)
+
+func TestSymbolConnection_NodesByFile(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "c", Path: "b.go", Line: 1},
+			{Name: "b", Path: "a.go", Line: 2},
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "d", Path: "b.go", Line: 2},
+		}, nil
+	}
+
+	ctx := context.Background()
+	commit := &GitCommitResolver{repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}, oid: "c1"}
+	first := int32(3)
+	orderBy := symbolOrderByName
+	descending := true
+	conn, err := commit.Symbols(ctx, &symbolsArgs{
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+		OrderBy:        &orderBy,
+		Descending:     &descending,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, err := conn.NodesByFile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]string{}
+	var paths []string
+	for _, group := range groups {
+		paths = append(paths, group.Path())
+		got[group.Path()] = symbolNames(group.Symbols())
+	}
+	// The page is the first 3 symbols in descending order of name (d, c, b), grouped by file.
+	if want := []string{"a.go", "b.go"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("got paths %v, want %v", paths, want)
+	}
+	if want := map[string][]string{"a.go": {"b"}, "b.go": {"c", "d"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package graphqlbackend
+
+import (
+	"regexp"
+	"strings"
+)
+
+// symbolNameRange is a range of a symbol's name, as byte offsets from the start
+// of the name.
+type symbolNameRange struct {
+	start, end int
+}
+
+// fuzzyHighlightPattern returns a regular expression that matches the same
+// names as fuzzySymbolPattern(query), with a group for each character of the
+// query. Its repetitions are lazy, so that each character matches as early in
+// the name as possible.
+func fuzzyHighlightPattern(query string, caseSensitive bool) *regexp.Regexp {
+	var b strings.Builder
+	if !caseSensitive {
+		b.WriteString("(?i)")
+	}
+	for i, r := range query {
+		if i > 0 {
+			b.WriteString(".*?")
+		}
+		b.WriteString("(" + regexp.QuoteMeta(string(r)) + ")")
+	}
+	return regexp.MustCompile(b.String())
+}
+
+// nameRanges reports whether the name of a symbol in the language satisfies
+// the query and, if so, returns the ranges of the name that matched it (or nil
+// if there is no query). For FUZZY queries, the ranges are the matched
+// characters (with adjacent characters joined); otherwise, the range is the
+// first match of the query.
+func (q *symbolQuery) nameRanges(name, language string) ([]symbolNameRange, bool) {
+	if q.re == nil {
+		return nil, true
+	}
+	if !q.re.MatchString(name) {
+		return nil, false
+	}
+	var ranges []symbolNameRange
+	switch {
+	case q.words != nil:
+		start, end, ok := indexWholeWords(name, q.literal, q.caseSensitive, q.words.camelCase(language))
+		if !ok {
+			return nil, false
+		}
+		ranges = append(ranges, symbolNameRange{start: start, end: end})
+	case q.fuzzy != nil:
+		m := q.fuzzy.FindStringSubmatchIndex(name)
+		for i := 2; i+1 < len(m); i += 2 {
+			if n := len(ranges); n > 0 && ranges[n-1].end == m[i] {
+				ranges[n-1].end = m[i+1]
+				continue
+			}
+			ranges = append(ranges, symbolNameRange{start: m[i], end: m[i+1]})
+		}
+	default:
+		m := q.re.FindStringIndex(name)
+		ranges = append(ranges, symbolNameRange{start: m[0], end: m[1]})
+	}
+	// Omit empty matches (e.g., of the regular expression "^").
+	nonEmpty := ranges[:0]
+	for _, r := range ranges {
+		if r.start < r.end {
+			nonEmpty = append(nonEmpty, r)
+		}
+	}
+	return nonEmpty, true
+}
+
+// matchRanges reports whether symbol satisfies the query and, if so, returns
+// the ranges of its name that matched it (see nameRanges).
+func (q *symbolQuery) matchRanges(symbol *symbolResolver) ([]symbolNameRange, bool) {
+	if ranges, ok := q.nameRanges(symbol.symbol.Name, symbol.language); ok || !q.qualified {
+		return ranges, ok
+	}
+	qualifiedName := symbol.languageQualifiedName()
+	ranges, ok := q.nameRanges(qualifiedName, symbol.language)
+	if !ok {
+		return nil, false
+	}
+	// The name is at the end of the qualified name, so only the parts of the ranges that are in
+	// the name are kept.
+	offset := len(qualifiedName) - len(symbol.symbol.Name)
+	nameRanges := ranges[:0]
+	for _, r := range ranges {
+		if r.end <= offset {
+			continue
+		}
+		if r.start < offset {
+			r.start = offset
+		}
+		nameRanges = append(nameRanges, symbolNameRange{start: r.start - offset, end: r.end - offset})
+	}
+	return nameRanges, true
+}
+
+// NameMatchRanges returns the ranges of the symbol's name that matched the query argument of the
+// symbol list, so that clients can highlight them. It is empty if there was no query.
+func (r *symbolResolver) NameMatchRanges() []*symbolNameRangeResolver {
+	res := make([]*symbolNameRangeResolver, len(r.nameMatchRanges))
+	for i, rng := range r.nameMatchRanges {
+		res[i] = &symbolNameRangeResolver{start: int32(rng.start), end: int32(rng.end)}
+	}
+	return res
+}
+
+// symbolNameRangeResolver is a range of a symbol's name, as byte offsets from
+// the start of the name.
+type symbolNameRangeResolver struct {
+	start, end int32
+}
+
+func (r *symbolNameRangeResolver) Start() int32 { return r.start }
+
+func (r *symbolNameRangeResolver) End() int32 { return r.end }
@@ -0,0 +1,74 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+)
+
+func TestSymbolQuery_matchRanges(t *testing.T) {
+	yes := true
+	tests := []struct {
+		name   string
+		args   symbolsArgs
+		symbol protocol.Symbol
+		want   []symbolNameRange
+	}{
+		{name: "no query", args: symbolsArgs{}, symbol: protocol.Symbol{Name: "newSymbolResolver"}, want: nil},
+		{name: "substring", args: symbolsArgs{Query: strptr("res")}, symbol: protocol.Symbol{Name: "newSymbolResolver"}, want: []symbolNameRange{{9, 12}}},
+		{name: "prefix", args: symbolsArgs{Query: strptr("new"), Match: strptr("PREFIX")}, symbol: protocol.Symbol{Name: "newSymbolResolver"}, want: []symbolNameRange{{0, 3}}},
+		{name: "fuzzy", args: symbolsArgs{Query: strptr("nsr"), Match: strptr("FUZZY")}, symbol: protocol.Symbol{Name: "newSymbolResolver"}, want: []symbolNameRange{{0, 1}, {3, 4}, {9, 10}}},
+		{name: "fuzzy adjacent", args: symbolsArgs{Query: strptr("nesy"), Match: strptr("FUZZY")}, symbol: protocol.Symbol{Name: "newSymbolResolver"}, want: []symbolNameRange{{0, 2}, {3, 5}}},
+		{name: "whole word", args: symbolsArgs{Query: strptr("get"), Match: strptr("WHOLE_WORD")}, symbol: protocol.Symbol{Name: "forgetGet"}, want: []symbolNameRange{{6, 9}}},
+		{name: "empty match", args: symbolsArgs{Query: strptr("^"), RegExp: &yes}, symbol: protocol.Symbol{Name: "x"}, want: []symbolNameRange{}},
+		{name: "qualified", args: symbolsArgs{Query: strptr("Reader.rea")}, symbol: protocol.Symbol{Name: "read", Parent: "Reader"}, want: []symbolNameRange{{0, 3}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := newSymbolQuery(&test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := q.matchRanges(&symbolResolver{symbol: test.symbol, language: "go"})
+			if !ok {
+				t.Fatal("got no match")
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestComputeSymbols_nameMatchRanges(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{{Name: "newReader", Path: "a.go", Line: 1}}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	// The second call is served from the cache.
+	for i := 0; i < 2; i++ {
+		symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Query: strptr("read")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(symbols) != 1 {
+			t.Fatalf("got %d symbols, want 1", len(symbols))
+		}
+		ranges := symbols[0].NameMatchRanges()
+		if len(ranges) != 1 || ranges[0].Start() != 3 || ranges[0].End() != 7 {
+			t.Errorf("call %d: got ranges %+v, want [3, 7)", i+1, ranges)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package graphqlbackend
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// lspSymbolKindsByName maps the names of the values of the GraphQL SymbolKind
+// enum (e.g., "ENUMMEMBER") to their LSP symbol kind.
+var lspSymbolKindsByName = func() map[string]lsp.SymbolKind {
+	m := map[string]lsp.SymbolKind{}
+	for kind := lsp.SKFile; kind <= lsp.SKTypeParameter; kind++ {
+		m[strings.ToUpper(kind.String())] = kind
+	}
+	return m
+}()
+
+// symbolLSPKind returns the LSP symbol kind of a symbol of the ctags kind in
+// the language. The site config "search.symbols.kindOverrides" takes
+// precedence over the language-independent mapping in ctagsKindToLSPSymbolKind,
+// because ctags parsers use the same kind names for different things (e.g.,
+// "type" is a struct in Go but a type alias in TypeScript).
+func symbolLSPKind(language, ctagsKind string) lsp.SymbolKind {
+	for k, name := range conf.SearchSymbolsKindOverrides()[language] {
+		if !strings.EqualFold(k, ctagsKind) {
+			continue
+		}
+		if kind, ok := lspSymbolKindsByName[strings.ToUpper(name)]; ok {
+			return kind
+		}
+	}
+	return ctagsKindToLSPSymbolKind(ctagsKind)
+}
+
+// ctagsKindsOfSymbolKinds returns the ctags kinds (in lowercase) that symbols
+// of the kinds (a set of names of values of the GraphQL SymbolKind enum, as
+// returned by symbolKindSet) may have, so that the symbols service can filter
+// symbols by kind. Because of the site config overrides, some symbols of these
+// ctags kinds may be of other kinds, so symbols must still be filtered by kind
+// afterward. It reports false if the ctags kinds can't be listed, because
+// UNKNOWN is one of the kinds (and it matches all unlisted ctags kinds).
+func ctagsKindsOfSymbolKinds(kinds map[string]struct{}) ([]string, bool) {
+	if len(kinds) == 0 {
+		return nil, false
+	}
+	if _, ok := kinds["UNKNOWN"]; ok {
+		return nil, false
+	}
+	set := map[string]struct{}{}
+	for name := range kinds {
+		for _, ctagsKind := range ctagsKindsByLSPSymbolKind[lspSymbolKindsByName[name]] {
+			set[ctagsKind] = struct{}{}
+		}
+	}
+	for _, overrides := range conf.SearchSymbolsKindOverrides() {
+		for ctagsKind, name := range overrides {
+			if _, ok := kinds[strings.ToUpper(name)]; ok {
+				set[strings.ToLower(ctagsKind)] = struct{}{}
+			}
+		}
+	}
+	ctagsKinds := make([]string, 0, len(set))
+	for ctagsKind := range set {
+		ctagsKinds = append(ctagsKinds, ctagsKind)
+	}
+	sort.Strings(ctagsKinds)
+	return ctagsKinds, true
+}
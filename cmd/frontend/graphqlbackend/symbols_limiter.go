@@ -0,0 +1,65 @@
+package graphqlbackend
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// errSymbolsBusy is returned when the frontend is already computing as many
+// symbol lists as the site config "search.symbols.maxConcurrency" allows.
+var errSymbolsBusy = errors.New("symbols: too many symbols requests are in progress, try again later")
+
+var (
+	symbolsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "src",
+		Subsystem: "graphql",
+		Name:      "symbols_in_flight",
+		Help:      "Number of symbol lists that are being computed.",
+	})
+	symbolsRejected = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "graphql",
+		Name:      "symbols_rejected_total",
+		Help:      "Number of symbols requests that failed because too many symbol lists were being computed.",
+	})
+)
+
+// symbolsLimiter bounds the number of symbol lists that are computed at the
+// same time, because each one holds all of its symbols in memory until the
+// request completes. Requests beyond the limit fail instead of waiting, so that
+// the frontend sheds load when it is saturated.
+//
+// The limit is read from the site config on every acquire (instead of sizing
+// a channel once), so that changes to it take effect immediately.
+type symbolsLimiter struct {
+	mu       sync.Mutex
+	inFlight int
+}
+
+var symbolsComputeLimiter = &symbolsLimiter{}
+
+// acquire reserves a slot for computing a symbol list. It returns
+// errSymbolsBusy if none is available. Otherwise, the caller must call release
+// when done.
+func (l *symbolsLimiter) acquire() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight >= conf.SearchSymbolsMaxConcurrency() {
+		symbolsRejected.Inc()
+		return errSymbolsBusy
+	}
+	l.inFlight++
+	symbolsInFlight.Inc()
+	return nil
+}
+
+func (l *symbolsLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	symbolsInFlight.Dec()
+}
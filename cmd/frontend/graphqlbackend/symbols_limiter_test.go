@@ -0,0 +1,27 @@
+package graphqlbackend
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSymbolsLimiter(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchSymbolsMaxConcurrency: 2}})
+	defer conf.Mock(nil)
+
+	l := &symbolsLimiter{}
+	for i := 0; i < 2; i++ {
+		if err := l.acquire(); err != nil {
+			t.Fatalf("acquire %d: %s", i, err)
+		}
+	}
+	if err := l.acquire(); err != errSymbolsBusy {
+		t.Fatalf("got error %v, want errSymbolsBusy", err)
+	}
+	l.release()
+	if err := l.acquire(); err != nil {
+		t.Fatalf("acquire after release: %s", err)
+	}
+}
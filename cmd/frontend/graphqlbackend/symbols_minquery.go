@@ -0,0 +1,26 @@
+package graphqlbackend
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// checkSymbolsQueryLength returns an error if the symbols of the whole tree (at
+// the root path) are listed with a query that is shorter than min characters,
+// which is the site config "search.symbols.minQueryLength" minimum for the
+// symbols source. Listing the symbols of a file or directory is not limited,
+// because they are few.
+func checkSymbolsQueryLength(source string, min int, path string, args *symbolsArgs) error {
+	if min <= 0 || strings.Trim(path, "/") != "" {
+		return nil
+	}
+	var query string
+	if args.Query != nil {
+		query = strings.TrimSpace(*args.Query)
+	}
+	if utf8.RuneCountInString(query) >= min {
+		return nil
+	}
+	return fmt.Errorf("the query must be at least %d characters long to list the symbols of the whole repository with the %s", min, source)
+}
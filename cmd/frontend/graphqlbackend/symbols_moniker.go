@@ -0,0 +1,52 @@
+package graphqlbackend
+
+// symbolMoniker identifies a symbol independently of where it is defined, so
+// that the same symbol defined (or re-exported) in several files can be
+// recognized.
+type symbolMoniker struct {
+	language, container, name string
+}
+
+func (r *symbolResolver) moniker() symbolMoniker {
+	return symbolMoniker{language: r.language, container: r.symbol.Parent, name: r.symbol.Name}
+}
+
+// betterSymbolLocation reports whether a is a better location than b for a
+// symbol that both have the moniker of: a definition is better than a
+// declaration (e.g., a re-export or prototype), then a symbol with more
+// metadata is better.
+func betterSymbolLocation(a, b *symbolResolver) bool {
+	if a.IsDefinition() != b.IsDefinition() {
+		return a.IsDefinition()
+	}
+	return symbolMetadataScore(a) > symbolMetadataScore(b)
+}
+
+// dedupeSymbolsByMoniker collapses the symbols with the same moniker into one,
+// keeping the one with the best location (see betterSymbolLocation) and
+// counting the others in its duplicateCount. Unlike dedupeSymbols, symbols in
+// different files are collapsed. The relative order of the remaining symbols is
+// preserved.
+func dedupeSymbolsByMoniker(symbols []*symbolResolver) []*symbolResolver {
+	seen := make(map[symbolMoniker]int, len(symbols))
+	deduped := symbols[:0]
+	for _, symbol := range symbols {
+		k := symbol.moniker()
+		if i, ok := seen[k]; ok {
+			duplicates := deduped[i].duplicateCount + 1
+			if betterSymbolLocation(symbol, deduped[i]) {
+				deduped[i] = symbol
+			}
+			deduped[i].duplicateCount = duplicates
+			continue
+		}
+		symbol.duplicateCount = 0
+		seen[k] = len(deduped)
+		deduped = append(deduped, symbol)
+	}
+	return deduped
+}
+
+// DuplicateCount returns the number of other symbols with the same language, container, and name
+// that were collapsed into this one by the deduplicateByMoniker argument.
+func (r *symbolResolver) DuplicateCount() int32 { return int32(r.duplicateCount) }
@@ -0,0 +1,65 @@
+package graphqlbackend
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// symbolNoiseAllLanguages is the key of the site config
+// "search.symbols.noisePatterns" whose patterns apply to all languages.
+const symbolNoiseAllLanguages = "*"
+
+// symbolNoisePatterns is the compiled form of the site config
+// "search.symbols.noisePatterns", keyed by language.
+type symbolNoisePatterns map[string][]*regexp.Regexp
+
+// newSymbolNoisePatterns compiles the site config noise patterns. Invalid
+// patterns are logged and ignored (the site config schema should have rejected
+// them), because they are not the fault of the user making the request.
+func newSymbolNoisePatterns() symbolNoisePatterns {
+	p := symbolNoisePatterns{}
+	for language, patterns := range conf.SearchSymbolsNoisePatterns() {
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				log15.Warn("Ignoring invalid pattern in site config search.symbols.noisePatterns.", "language", language, "pattern", pattern, "error", err)
+				continue
+			}
+			key := strings.ToLower(language)
+			p[key] = append(p[key], re)
+		}
+	}
+	return p
+}
+
+// isNoise reports whether the symbol should be omitted from symbol lists,
+// because it has an empty name or its name matches a noise pattern for its
+// language.
+func (p symbolNoisePatterns) isNoise(symbol *symbolResolver) bool {
+	name := symbol.symbol.Name
+	if strings.TrimSpace(name) == "" {
+		return true
+	}
+	for _, key := range []string{symbol.language, symbolNoiseAllLanguages} {
+		for _, re := range p[key] {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filterNoiseSymbols returns the symbols that are not noise (see isNoise).
+func filterNoiseSymbols(symbols []*symbolResolver, p symbolNoisePatterns) []*symbolResolver {
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if !p.isNoise(symbol) {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,124 @@
+package graphqlbackend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	lsp "github.com/sourcegraph/go-lsp"
+)
+
+// The values of the GraphQL SymbolOrderBy enum.
+const (
+	symbolOrderByLocation  = "LOCATION"
+	symbolOrderByName      = "NAME"
+	symbolOrderByKind      = "KIND"
+	symbolOrderByRelevance = "RELEVANCE"
+)
+
+// symbolOrder is the order of the symbols in a symbol connection, as given by
+// the orderBy and descending arguments.
+type symbolOrder struct {
+	by         string // a SymbolOrderBy enum value
+	descending bool
+}
+
+// defaultSymbolOrder orders symbols by location, which is the natural order
+// when browsing files.
+var defaultSymbolOrder = &symbolOrder{by: symbolOrderByLocation}
+
+func newSymbolOrder(args *symbolsArgs) (*symbolOrder, error) {
+	o := &symbolOrder{by: symbolOrderByLocation, descending: args.Descending != nil && *args.Descending}
+	if args.OrderBy != nil {
+		o.by = strings.ToUpper(*args.OrderBy)
+	}
+	switch o.by {
+	case symbolOrderByLocation, symbolOrderByName, symbolOrderByKind, symbolOrderByRelevance:
+		return o, nil
+	default:
+		return nil, fmt.Errorf("invalid symbol order %q", *args.OrderBy)
+	}
+}
+
+// less reports whether the symbol at cursor a sorts before the symbol at
+// cursor b. Every order falls back to ordering by location (and then name), so
+// that it is total and pages of results are stable across calls.
+//
+// The descending argument only reverses the requested order: ties are always
+// broken in ascending order of location, so that symbols in the same file
+// that tie are listed in declaration order (e.g., overloads when ordering by
+// name), however the backend returned them.
+func (o *symbolOrder) less(a, b *symbolCursor) bool {
+	if o.by == symbolOrderByLocation {
+		if o.descending {
+			return b.less(a)
+		}
+		return a.less(b)
+	}
+	x, y := a, b
+	if o.descending {
+		x, y = b, a
+	}
+	switch o.by {
+	case symbolOrderByName:
+		if xn, yn := strings.ToLower(x.Name), strings.ToLower(y.Name); xn != yn {
+			return xn < yn
+		}
+	case symbolOrderByRelevance:
+		if x.Score != y.Score {
+			return x.Score > y.Score
+		}
+	case symbolOrderByKind:
+		if xr, yr := symbolKindRank(x.Kind), symbolKindRank(y.Kind); xr != yr {
+			return xr < yr
+		}
+		if xn, yn := strings.ToLower(x.Name), strings.ToLower(y.Name); xn != yn {
+			return xn < yn
+		}
+	}
+	return a.less(b)
+}
+
+// symbolKindRank returns the position of the group of kind when ordering
+// symbols by kind: types first, then functions, then members, then variables
+// and constants, then modules and other containers, then everything else.
+func symbolKindRank(kind lsp.SymbolKind) int {
+	switch kind {
+	case lsp.SKClass, lsp.SKInterface, lsp.SKStruct, lsp.SKEnum, lsp.SKTypeParameter:
+		return 0
+	case lsp.SKFunction, lsp.SKMethod, lsp.SKConstructor, lsp.SKOperator:
+		return 1
+	case lsp.SKField, lsp.SKProperty, lsp.SKEnumMember, lsp.SKEvent:
+		return 2
+	case lsp.SKVariable, lsp.SKConstant:
+		return 3
+	case lsp.SKModule, lsp.SKNamespace, lsp.SKPackage, lsp.SKFile:
+		return 4
+	case 0:
+		return 6
+	default:
+		return 5
+	}
+}
+
+// sortSymbols sorts symbols in the given order, so that results (and pages of
+// results) are stable across calls regardless of the order in which the
+// backend returned them.
+func sortSymbols(symbols []*symbolResolver, order *symbolOrder) {
+	// Compute the cursor of each symbol once instead of on every comparison, because computing its
+	// kind reads the site config.
+	type keyedSymbol struct {
+		symbol *symbolResolver
+		cursor *symbolCursor
+	}
+	keyed := make([]keyedSymbol, len(symbols))
+	for i, symbol := range symbols {
+		keyed[i] = keyedSymbol{symbol: symbol, cursor: symbolCursorFor(symbol)}
+	}
+	sort.SliceStable(keyed, func(i, j int) bool {
+		return order.less(keyed[i].cursor, keyed[j].cursor)
+	})
+	for i, k := range keyed {
+		symbols[i] = k.symbol
+	}
+}
@@ -0,0 +1,98 @@
+package graphqlbackend
+
+import (
+	"fmt"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	lsp "github.com/sourcegraph/go-lsp"
+)
+
+// symbolCursor represents a decoded symbol pagination cursor. From an API
+// consumer standpoint, it is an encoded opaque string.
+//
+// A cursor holds the sort keys of a symbol (see symbolOrder) rather than its
+// index in the list, so that it identifies the same position in the list across
+// calls. This also keeps paging working (on a best-effort basis) when the list
+// changes between calls, e.g., because the indexed revision changed: the
+// cursor's symbol need not be in the new list, because symbolsBetweenCursors
+// compares the keys of the listed symbols to the cursor's.
+type symbolCursor struct {
+	Path      string
+	Line      int
+	Character int
+	Name      string
+	Kind      lsp.SymbolKind `json:",omitempty"`
+	Score     int32          `json:",omitempty"`
+}
+
+const symbolCursorKind = "SymbolCursor"
+
+// marshalSymbolCursor marshals a symbol pagination cursor.
+func marshalSymbolCursor(c *symbolCursor) string {
+	return string(relay.MarshalID(symbolCursorKind, c))
+}
+
+// unmarshalSymbolCursor unmarshals a symbol pagination cursor.
+func unmarshalSymbolCursor(cursor *string) (*symbolCursor, error) {
+	if cursor == nil {
+		return nil, nil
+	}
+	if kind := relay.UnmarshalKind(graphql.ID(*cursor)); kind != symbolCursorKind {
+		return nil, fmt.Errorf("cannot unmarshal symbol cursor type: %q", kind)
+	}
+	var spec *symbolCursor
+	if err := relay.UnmarshalSpec(graphql.ID(*cursor), &spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// symbolCursorFor returns the cursor that points at symbol.
+func symbolCursorFor(symbol *symbolResolver) *symbolCursor {
+	start := symbol.location.lspRange.Start
+	c := &symbolCursor{
+		Path:      symbol.uri.Fragment,
+		Line:      start.Line,
+		Character: start.Character,
+		Name:      symbol.symbol.Name,
+		Kind:      symbolLSPKind(symbol.language, symbol.symbol.Kind),
+	}
+	if symbol.score != nil {
+		c.Score = *symbol.score
+	}
+	return c
+}
+
+// less reports whether c sorts before other by location (and then name).
+func (c *symbolCursor) less(other *symbolCursor) bool {
+	if c.Path != other.Path {
+		return c.Path < other.Path
+	}
+	if c.Line != other.Line {
+		return c.Line < other.Line
+	}
+	if c.Character != other.Character {
+		return c.Character < other.Character
+	}
+	return c.Name < other.Name
+}
+
+// symbolsBetweenCursors returns the symbols (which must be sorted in order)
+// that come strictly after the after cursor and strictly before the before
+// cursor. Either cursor may be nil. It also reports whether any symbols were
+// omitted from the start and end of the list, respectively.
+func symbolsBetweenCursors(symbols []*symbolResolver, after, before *symbolCursor, order *symbolOrder) (window []*symbolResolver, omittedStart, omittedEnd bool) {
+	start, end := 0, len(symbols)
+	if after != nil {
+		for start < end && !order.less(after, symbolCursorFor(symbols[start])) {
+			start++
+		}
+	}
+	if before != nil {
+		for end > start && !order.less(symbolCursorFor(symbols[end-1]), before) {
+			end--
+		}
+	}
+	return symbols[start:end], start > 0, end < len(symbols)
+}
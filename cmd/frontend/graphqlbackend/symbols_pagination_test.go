@@ -0,0 +1,145 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+)
+
+// TestSymbolConnection_cursorAfterListChanged tests that paging resumes at the
+// right place when the list of symbols changes between requests (e.g., because
+// the indexed revision changed), even if the cursor's symbol no longer exists.
+func TestSymbolConnection_cursorAfterListChanged(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	symbolsAt := map[string][]protocol.Symbol{
+		"c1": {
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "b", Path: "a.go", Line: 2},
+			{Name: "c", Path: "a.go", Line: 3},
+			{Name: "d", Path: "b.go", Line: 1},
+		},
+		// b (the end of the first page) was deleted, a symbol was added before it, and c moved up.
+		"c2": {
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "a2", Path: "a.go", Line: 2},
+			{Name: "c", Path: "a.go", Line: 2},
+			{Name: "d", Path: "b.go", Line: 1},
+		},
+	}
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return symbolsAt[string(args.CommitID)], nil
+	}
+
+	ctx := context.Background()
+	repo := &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}
+	first := int32(2)
+	conn, err := (&GitCommitResolver{repo: repo, oid: "c1"}).Symbols(ctx, &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, _ := conn.Nodes(ctx)
+	if got, want := symbolNames(nodes), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got first page %v, want %v", got, want)
+	}
+	pageInfo, _ := conn.PageInfo(ctx)
+
+	conn, err = (&GitCommitResolver{repo: repo, oid: "c2"}).Symbols(ctx, &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, After: pageInfo.EndCursor()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, _ = conn.Nodes(ctx)
+	if got, want := symbolNames(nodes), []string{"c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got second page %v, want %v", got, want)
+	}
+}
+
+// TestSymbolConnection_cursorsPastSymbolsServiceLimit tests that paging with
+// cursors reaches the symbols after the first page of the symbols service
+// (which returns at most protocol.MaxFirst symbols per request).
+func TestSymbolConnection_cursorsPastSymbolsServiceLimit(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	const n = 1234
+	backend.Mocks.Symbols.ListTags = mockPagedListTags(manySymbols(n))
+
+	ctx := context.Background()
+	commit := &GitCommitResolver{repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}, oid: "c1"}
+	first := int32(100)
+	seen := 0
+	var after *string
+	for pages := 0; ; pages++ {
+		if pages > n/int(first)+1 {
+			t.Fatal("too many pages")
+		}
+		conn, err := commit.Symbols(ctx, &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, After: after})
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes, _ := conn.Nodes(ctx)
+		seen += len(nodes)
+		pageInfo, _ := conn.PageInfo(ctx)
+		if !pageInfo.HasNextPage() {
+			break
+		}
+		after = pageInfo.EndCursor()
+	}
+	if seen != n {
+		t.Errorf("got %d symbols in all pages, want %d", seen, n)
+	}
+}
+
+// unsortedListTags returns a ListTags mock that returns the symbols in the
+// given (not necessarily sorted) order, honoring the limit like the symbols
+// service.
+func unsortedListTags(symbols ...protocol.Symbol) func(context.Context, search.SymbolsParameters) ([]protocol.Symbol, error) {
+	return func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		if len(symbols) > args.First {
+			return symbols[:args.First], nil
+		}
+		return symbols, nil
+	}
+}
+
+// TestSymbolConnection_cursorsUnsortedSource tests that paging with cursors
+// visits every symbol once, in order, when the symbols source returns the
+// symbols in a different order.
+func TestSymbolConnection_cursorsUnsortedSource(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	backend.Mocks.Symbols.ListTags = unsortedListTags(
+		protocol.Symbol{Name: "c", Path: "a.go", Line: 3},
+		protocol.Symbol{Name: "b", Path: "a.go", Line: 2},
+		protocol.Symbol{Name: "a", Path: "a.go", Line: 1},
+	)
+
+	ctx := context.Background()
+	commit := &GitCommitResolver{repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}, oid: "c1"}
+	first := int32(1)
+	var got []string
+	var after *string
+	for pages := 0; pages < 5; pages++ {
+		conn, err := commit.Symbols(ctx, &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, After: after})
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes, _ := conn.Nodes(ctx)
+		got = append(got, symbolNames(nodes)...)
+		pageInfo, _ := conn.PageInfo(ctx)
+		if !pageInfo.HasNextPage() {
+			break
+		}
+		after = pageInfo.EndCursor()
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+package graphqlbackend
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// symbolPathPatterns is the compiled form of the includePatterns and
+// excludePatterns arguments of a symbols request.
+//
+// Like the file: and -file: search query filters, the patterns are regular
+// expressions (not globs) that are matched against the file path of each
+// symbol, relative to the repository root and without a leading slash. Like
+// the query, they are matched case-insensitively unless the caseSensitive
+// argument is true (as the symbols service matches them). They are not
+// implicitly anchored, so "src/api/" matches any path containing "src/api/";
+// use "^src/api/" to match only paths under the top-level src/api directory.
+//
+// If languages are given, a pattern that matches the file extensions of any of
+// the languages (like the lang: search query filter) is added to the include
+// patterns. If excludeGenerated is true, the site config
+// "search.symbols.generatedPatterns" patterns (which match vendored and
+// generated files) are added to the exclude patterns.
+type symbolPathPatterns struct {
+	// include and exclude are the patterns as given by the caller. They are
+	// passed to the symbols backends so that they can skip non-matching files.
+	include []string
+	exclude []string
+
+	// caseSensitive is whether include and exclude are matched case-sensitively.
+	caseSensitive bool
+
+	includeRes []*regexp.Regexp
+	excludeRes []*regexp.Regexp
+
+	// files are the paths given by the paths argument (sorted, and without
+	// leading or trailing slashes), which symbols' file paths must be one of if
+	// there are any. fileSet is the same paths as a set.
+	files   []string
+	fileSet map[string]bool
+}
+
+// maxSymbolsPaths is the maximum number of paths that the paths argument of a
+// symbols request may have. The symbols service matches them with a query
+// parameter each, and SQLite limits the number of query parameters.
+const maxSymbolsPaths = 500
+
+func newSymbolPathPatterns(args *symbolsArgs) (*symbolPathPatterns, error) {
+	p := &symbolPathPatterns{caseSensitive: args.CaseSensitive != nil && *args.CaseSensitive}
+	if args.IncludePatterns != nil {
+		p.include = append(p.include, *args.IncludePatterns...)
+	}
+	if args.ExcludePatterns != nil {
+		p.exclude = append(p.exclude, *args.ExcludePatterns...)
+	}
+	if args.Languages != nil && len(*args.Languages) > 0 {
+		// A symbol's file must be in any (not all) of the languages.
+		languagePatterns, _, err := langIncludeExcludePatterns(*args.Languages, nil)
+		if err != nil {
+			return nil, err
+		}
+		p.include = append(p.include, unionRegExps(languagePatterns))
+	}
+	if args.ExcludeGenerated != nil && *args.ExcludeGenerated {
+		p.exclude = append(p.exclude, conf.SearchSymbolsGeneratedPatterns()...)
+	}
+	if args.Paths != nil && len(*args.Paths) > 0 {
+		if len(*args.Paths) > maxSymbolsPaths {
+			return nil, fmt.Errorf("symbols: at most %d paths may be given", maxSymbolsPaths)
+		}
+		p.fileSet = make(map[string]bool, len(*args.Paths))
+		for _, path := range *args.Paths {
+			path = strings.Trim(path, "/")
+			if path == "" {
+				return nil, fmt.Errorf("symbols: invalid empty path")
+			}
+			if !p.fileSet[path] {
+				p.fileSet[path] = true
+				p.files = append(p.files, path)
+			}
+		}
+		sort.Strings(p.files)
+	}
+	var err error
+	if p.includeRes, err = compileSymbolPathPatterns(p.include, p.caseSensitive); err != nil {
+		return nil, err
+	}
+	if p.excludeRes, err = compileSymbolPathPatterns(p.exclude, p.caseSensitive); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func compileSymbolPathPatterns(patterns []string, caseSensitive bool) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid symbol path pattern %q: %s", pattern, err)
+		}
+		if !caseSensitive {
+			pattern = "(?i:" + pattern + ")"
+		}
+		res = append(res, regexp.MustCompile(pattern))
+	}
+	return res, nil
+}
+
+// excludePattern returns a single regular expression that matches the paths
+// matched by any of the exclude patterns, or "" if there are none.
+func (p *symbolPathPatterns) excludePattern() string {
+	return unionRegExps(p.exclude)
+}
+
+// filesPattern returns a regular expression that matches exactly the paths
+// given by the paths argument.
+func (p *symbolPathPatterns) filesPattern() string {
+	quoted := make([]string, len(p.files))
+	for i, path := range p.files {
+		quoted[i] = regexp.QuoteMeta(path)
+	}
+	return "^(?:" + strings.Join(quoted, "|") + ")$"
+}
+
+// match reports whether the file path is one of the paths given by the paths
+// argument (if any), matches all of the include patterns, and matches none of
+// the exclude patterns.
+func (p *symbolPathPatterns) match(path string) bool {
+	if p.fileSet != nil && !p.fileSet[path] {
+		return false
+	}
+	for _, re := range p.includeRes {
+		if !re.MatchString(path) {
+			return false
+		}
+	}
+	for _, re := range p.excludeRes {
+		if re.MatchString(path) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterSymbolsByPathPatterns returns the symbols defined in files whose paths
+// match p.
+func filterSymbolsByPathPatterns(symbols []*symbolResolver, p *symbolPathPatterns) []*symbolResolver {
+	if len(p.includeRes) == 0 && len(p.excludeRes) == 0 && p.fileSet == nil {
+		return symbols
+	}
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if p.match(symbol.uri.Fragment) {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
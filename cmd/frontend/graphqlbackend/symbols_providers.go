@@ -0,0 +1,105 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/src-d/enry/v2"
+)
+
+// ctagsLanguageAliases maps the lowercase names of languages (as reported by
+// language detection in the inventory) to the lowercase names of the ctags
+// parsers for them, where they differ.
+var ctagsLanguageAliases = map[string]string{
+	"shell":        "sh",
+	"objective-c":  "objectivec",
+	"perl 6":       "perl6",
+	"raku":         "perl6",
+	"vim script":   "vim",
+	"common lisp":  "lisp",
+	"emacs lisp":   "lisp",
+	"visual basic": "basic",
+	"scss":         "css",
+	"less":         "css",
+	"sass":         "css",
+}
+
+// ctagsLanguages is the set of the lowercase names of the ctags parsers that
+// the symbols service uses.
+var ctagsLanguages = func() map[string]bool {
+	m := make(map[string]bool, len(protocol.Languages))
+	for _, language := range protocol.Languages {
+		m[strings.ToLower(language)] = true
+	}
+	return m
+}()
+
+// hasSymbolProvider reports whether symbols are listed for files in the language
+// (named as in the inventory, e.g., "C++").
+func hasSymbolProvider(language string) bool {
+	name := strings.ToLower(language)
+	if alias, ok := ctagsLanguageAliases[name]; ok {
+		name = alias
+	}
+	return ctagsLanguages[name]
+}
+
+// SymbolProviders returns whether symbols are available for each of the languages in the tree at
+// the commit, so that clients can tell users which languages have no symbols without listing them.
+// The languages come from the commit's inventory (which is cached), so this does not compute any
+// symbols. Clients that already know the languages can pass them instead, which avoids computing
+// the inventory.
+func (r *GitCommitResolver) SymbolProviders(ctx context.Context, args *struct {
+	Languages *[]string
+}) ([]*symbolProviderResolver, error) {
+	languages, err := symbolProviderLanguages(args.Languages)
+	if err != nil {
+		return nil, err
+	}
+	if len(languages) == 0 {
+		inventory, err := backend.Repos.GetInventory(ctx, r.repo.repo, api.CommitID(r.oid), false)
+		if err != nil {
+			return nil, err
+		}
+		for _, lang := range inventory.Languages {
+			languages = append(languages, lang.Name)
+		}
+	}
+	providers := make([]*symbolProviderResolver, 0, len(languages))
+	for _, language := range languages {
+		providers = append(providers, &symbolProviderResolver{language: language, available: hasSymbolProvider(language)})
+	}
+	return providers, nil
+}
+
+// symbolProviderLanguages returns the names (as in the inventory) of the
+// languages given by the client, which may be any of their aliases (like the
+// lang: search filter). It returns an error if any language is unknown.
+func symbolProviderLanguages(values *[]string) ([]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	languages := make([]string, 0, len(*values))
+	for _, value := range *values {
+		lang, ok := enry.GetLanguageByAlias(value)
+		if !ok {
+			return nil, fmt.Errorf("unknown language: %q", value)
+		}
+		languages = append(languages, lang)
+	}
+	return languages, nil
+}
+
+// symbolProviderResolver is whether symbols are available for a language.
+type symbolProviderResolver struct {
+	language  string
+	available bool
+}
+
+func (r *symbolProviderResolver) Language() string { return r.language }
+
+func (r *symbolProviderResolver) Available() bool { return r.available }
@@ -0,0 +1,73 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestGitCommitResolver_SymbolProviders(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Repos.GetInventory = func(_ context.Context, _ *types.Repo, commitID api.CommitID) (*inventory.Inventory, error) {
+		if commitID != "c1" {
+			t.Errorf("got commit %q, want c1", commitID)
+		}
+		return &inventory.Inventory{
+			Languages: []inventory.Lang{
+				{Name: "Go"},
+				{Name: "C++"},
+				{Name: "Shell"},
+				{Name: "Markdown"},
+			},
+		}, nil
+	}
+	defer func() { backend.Mocks.Repos.GetInventory = nil }()
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	providers, err := commit.SymbolProviders(context.Background(), &struct{ Languages *[]string }{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"Go": true, "C++": true, "Shell": true, "Markdown": false}
+	if got := symbolProvidersByLanguage(providers); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Languages given by the client are used instead of the inventory.
+	backend.Mocks.Repos.GetInventory = func(context.Context, *types.Repo, api.CommitID) (*inventory.Inventory, error) {
+		t.Fatal("GetInventory was called, want the given languages to be used")
+		return nil, nil
+	}
+	languages := []string{"go", "markdown"}
+	providers, err = commit.SymbolProviders(context.Background(), &struct{ Languages *[]string }{Languages: &languages})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]bool{"Go": true, "Markdown": false}
+	if got := symbolProvidersByLanguage(providers); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	languages = []string{"go", "notalanguage"}
+	if _, err := commit.SymbolProviders(context.Background(), &struct{ Languages *[]string }{Languages: &languages}); err == nil {
+		t.Error("got nil error for an unknown language")
+	}
+}
+
+func symbolProvidersByLanguage(providers []*symbolProviderResolver) map[string]bool {
+	m := map[string]bool{}
+	for _, p := range providers {
+		m[p.Language()] = p.Available()
+	}
+	return m
+}
@@ -0,0 +1,214 @@
+package graphqlbackend
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The values of the GraphQL SymbolMatch enum.
+const (
+	symbolMatchPrefix    = "PREFIX"
+	symbolMatchSubstring = "SUBSTRING"
+	symbolMatchFuzzy     = "FUZZY"
+	symbolMatchWholeWord = "WHOLE_WORD"
+)
+
+// symbolQuery is the compiled form of the query arguments of a symbols
+// request.
+//
+// The symbols backends (indexed search and the symbols service) interpret the
+// query themselves, and their semantics differ in small ways. To give callers
+// consistent results, the query is also compiled to a Go regular expression
+// that is matched against the name of every symbol returned by a backend.
+// Because Go regular expressions are guaranteed to run in time linear in the
+// size of the input, user-supplied patterns cannot cause catastrophic
+// backtracking.
+type symbolQuery struct {
+	// pattern is the regular expression sent to the symbols backends.
+	pattern string
+
+	// caseSensitive is whether pattern is matched case-sensitively.
+	caseSensitive bool
+
+	// re matches the names of symbols that satisfy the query. It is nil if
+	// there is no query.
+	re *regexp.Regexp
+
+	// qualified is whether the query is a qualified name (e.g., "Foo.Bar"), in
+	// which case re is also matched against the names of symbols qualified by
+	// their container names (see languageQualifiedName) and pattern also
+	// matches names that satisfy the last component of the query.
+	qualified bool
+
+	// words is how names are split into words if the query must match whole
+	// words of names (the WHOLE_WORD match mode), in which case literal is the
+	// query. Otherwise it is nil. Because regular expressions can't express
+	// camelCase word boundaries, re (and pattern) only match names that contain
+	// the query, and the word boundaries are checked separately.
+	words   symbolWordBoundaries
+	literal string
+
+	// fuzzy matches the same names as re if the query is matched in the FUZZY
+	// match mode, with a group for each character of the query, so that the
+	// matched characters can be highlighted (see nameRanges). Otherwise it is
+	// nil.
+	fuzzy *regexp.Regexp
+}
+
+// qualifiedSymbolQuery matches queries that are qualified names: identifiers
+// joined by container separators (see symbolContainerSeparator). Only queries
+// that are not explicitly regular expressions are treated as qualified names.
+var qualifiedSymbolQuery = regexp.MustCompile(`^[\w$]+(?:(?:\.|::|#)[\w$]*)+$`)
+
+func newSymbolQuery(args *symbolsArgs) (*symbolQuery, error) {
+	q, err := compileSymbolQuery(args.Query, args)
+	if err != nil || q.re == nil || (args.RegExp != nil && *args.RegExp) || !qualifiedSymbolQuery.MatchString(*args.Query) {
+		// Explicit regular expressions are never qualified names, because "." is
+		// a metacharacter in them.
+		return q, err
+	}
+
+	// The backends match the query against symbol names only, so also send
+	// them the last component of the qualified name and match the full query
+	// against qualified names afterward. The full query is kept for symbols
+	// whose names contain separators (e.g., "module.exports" in JavaScript).
+	name := (*args.Query)[strings.LastIndexAny(*args.Query, ".:#")+1:]
+	nameQuery, err := compileSymbolQuery(&name, args)
+	if err != nil {
+		return nil, err
+	}
+	if nameQuery.pattern == "" {
+		q.pattern = ""
+	} else {
+		q.pattern += "|" + nameQuery.pattern
+	}
+	q.qualified = true
+	return q, nil
+}
+
+// symbolContainerSeparator returns the separator between the container name
+// and name of a symbol in the language, as it is conventionally written in
+// qualified names.
+func symbolContainerSeparator(language string) string {
+	switch language {
+	case "c", "c++", "cpp", "rust", "php", "perl":
+		return "::"
+	case "ruby":
+		return "#"
+	}
+	return "."
+}
+
+// newSymbolContainerQuery returns the compiled form of the containerQuery
+// argument, which is matched against the container names of symbols with the
+// same options (regexp, match, and caseSensitive) as the query argument. It is
+// only applied after the symbols are fetched from the backends, because their
+// queries only match names.
+func newSymbolContainerQuery(args *symbolsArgs) (*symbolQuery, error) {
+	q, err := compileSymbolQuery(args.ContainerQuery, args)
+	if err != nil {
+		return nil, fmt.Errorf("containerQuery: %s", err)
+	}
+	return q, nil
+}
+
+// compileSymbolQuery compiles query with the query options in args.
+func compileSymbolQuery(query *string, args *symbolsArgs) (*symbolQuery, error) {
+	q := &symbolQuery{caseSensitive: args.CaseSensitive != nil && *args.CaseSensitive}
+	if query == nil || *query == "" {
+		return q, nil
+	}
+
+	q.pattern = *query
+	if args.Match != nil {
+		// The match modes are defined for literal queries, and are compiled to
+		// regular expressions so that the backends can apply them, too.
+		if args.RegExp != nil && *args.RegExp {
+			return nil, fmt.Errorf("the symbol match mode %q cannot be combined with a regular expression query", *args.Match)
+		}
+		switch strings.ToUpper(*args.Match) {
+		case symbolMatchPrefix:
+			q.pattern = "^" + regexp.QuoteMeta(q.pattern)
+		case symbolMatchSubstring:
+			q.pattern = regexp.QuoteMeta(q.pattern)
+		case symbolMatchFuzzy:
+			q.pattern = fuzzySymbolPattern(q.pattern)
+			q.fuzzy = fuzzyHighlightPattern(*query, q.caseSensitive)
+		case symbolMatchWholeWord:
+			q.pattern = regexp.QuoteMeta(q.pattern)
+			q.words, q.literal = newSymbolWordBoundaries(), *query
+		default:
+			return nil, fmt.Errorf("invalid symbol match mode %q", *args.Match)
+		}
+	} else if args.RegExp != nil && !*args.RegExp {
+		q.pattern = regexp.QuoteMeta(q.pattern)
+	}
+	expr := q.pattern
+	if !q.caseSensitive {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid symbol query regular expression: %s", err)
+	}
+	q.re = re
+	return q, nil
+}
+
+// fuzzySymbolPattern returns a regular expression that matches the names that
+// contain all of the characters of query, in order (e.g., "nsr" matches
+// "newSymbolResolver").
+func fuzzySymbolPattern(query string) string {
+	var b strings.Builder
+	for i, r := range query {
+		if i > 0 {
+			b.WriteString(".*")
+		}
+		b.WriteString(regexp.QuoteMeta(string(r)))
+	}
+	return b.String()
+}
+
+// match reports whether symbol satisfies the query.
+func (q *symbolQuery) match(symbol *symbolResolver) bool {
+	_, ok := q.matchRanges(symbol)
+	return ok
+}
+
+// matchName reports whether the name of a symbol in the language satisfies the
+// query.
+func (q *symbolQuery) matchName(name, language string) bool {
+	_, ok := q.nameRanges(name, language)
+	return ok
+}
+
+// filterSymbolsByContainerQuery returns the symbols whose container name
+// satisfies q.
+func filterSymbolsByContainerQuery(symbols []*symbolResolver, q *symbolQuery) []*symbolResolver {
+	if q.re == nil {
+		return symbols
+	}
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if q.matchName(symbol.symbol.Parent, symbol.language) {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
+
+// filterSymbolsByQuery returns the symbols that satisfy q.
+func filterSymbolsByQuery(symbols []*symbolResolver, q *symbolQuery) []*symbolResolver {
+	if q.re == nil {
+		return symbols
+	}
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if ranges, ok := q.matchRanges(symbol); ok {
+			symbol.nameMatchRanges = ranges
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
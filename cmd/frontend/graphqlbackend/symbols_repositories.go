@@ -0,0 +1,148 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/neelance/parallel"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// maxSymbolsRepositories is the maximum number of repositories whose symbols
+// can be listed in a single Query.symbols request.
+const maxSymbolsRepositories = 100
+
+type repositoriesSymbolsArgs struct {
+	Repositories []string
+	symbolsArgs
+}
+
+// Symbols lists the symbols defined at the HEAD commit of each of the given repositories.
+func (r *schemaResolver) Symbols(ctx context.Context, args *repositoriesSymbolsArgs) (*repositoriesSymbolConnectionResolver, error) {
+	if len(args.Repositories) > maxSymbolsRepositories {
+		return nil, fmt.Errorf("too many repositories (%d): symbols can be listed for at most %d repositories at a time", len(args.Repositories), maxSymbolsRepositories)
+	}
+	// Check the arguments up front, so that invalid arguments are reported once instead of as a
+	// failure for every repository.
+	if err := validateSymbolsFirst(args.First); err != nil {
+		return nil, err
+	}
+	if err := validateSymbolsBodyQuery(&args.symbolsArgs); err != nil {
+		return nil, err
+	}
+	if err := validateSymbolsSince(&args.symbolsArgs); err != nil {
+		return nil, err
+	}
+	if _, err := newSymbolBodyQuery(&args.symbolsArgs); err != nil {
+		return nil, err
+	}
+	if _, err := newSymbolQuery(&args.symbolsArgs); err != nil {
+		return nil, err
+	}
+	if _, err := newSymbolContainerQuery(&args.symbolsArgs); err != nil {
+		return nil, err
+	}
+	if _, err := symbolKindSet(args.Kinds); err != nil {
+		return nil, err
+	}
+	if _, err := newSymbolPathPatterns(&args.symbolsArgs); err != nil {
+		return nil, err
+	}
+
+	var (
+		run = parallel.NewRun(conf.SearchSymbolsParallelism())
+		mu  sync.Mutex
+		res = &repositoriesSymbolConnectionResolver{}
+	)
+	for _, name := range args.Repositories {
+		name := name
+		run.Acquire()
+		if ctx.Err() != nil {
+			// The request was abandoned (possibly while waiting for a worker), so don't start
+			// any work for the remaining repositories.
+			run.Release()
+			break
+		}
+		goroutine.Go(func() {
+			defer run.Release()
+			symbols, common, err := computeRepositorySymbols(ctx, api.RepoName(name), &args.symbolsArgs)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				res.errors = append(res.errors, &repositorySymbolsErrorResolver{repository: name, err: err})
+				return
+			}
+			for _, e := range common.sourceErrors {
+				res.errors = append(res.errors, &repositorySymbolsErrorResolver{repository: name, err: e})
+			}
+			res.symbols = append(res.symbols, symbols...)
+			res.limitHit = res.limitHit || common.limitHit
+		})
+	}
+	run.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(res.symbols, func(i, j int) bool {
+		a, b := res.symbols[i], res.symbols[j]
+		if a.uri.Repo() != b.uri.Repo() {
+			return a.uri.Repo() < b.uri.Repo()
+		}
+		return symbolLess(a, b)
+	})
+	if limit := limitOrDefault(args.First); len(res.symbols) > limit {
+		res.symbols = res.symbols[:limit]
+		res.limitHit = true
+	}
+	sort.Slice(res.errors, func(i, j int) bool { return res.errors[i].repository < res.errors[j].repository })
+	return res, nil
+}
+
+// computeRepositorySymbols returns the symbols defined at the HEAD commit of the
+// named repository.
+func computeRepositorySymbols(ctx context.Context, name api.RepoName, args *symbolsArgs) ([]*symbolResolver, symbolsCommon, error) {
+	repo, err := backend.Repos.GetByName(ctx, name)
+	if err != nil {
+		return nil, symbolsCommon{}, err
+	}
+	commitID, err := backend.Repos.ResolveRev(ctx, repo, "")
+	if err != nil {
+		return nil, symbolsCommon{}, err
+	}
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: repo},
+		oid:  GitObjectID(commitID),
+	}
+	return computeSymbols(ctx, commit, "", args)
+}
+
+type repositoriesSymbolConnectionResolver struct {
+	symbols  []*symbolResolver
+	limitHit bool
+	errors   []*repositorySymbolsErrorResolver
+}
+
+func (r *repositoriesSymbolConnectionResolver) Nodes() []*symbolResolver { return r.symbols }
+
+func (r *repositoriesSymbolConnectionResolver) LimitHit() bool { return r.limitHit }
+
+func (r *repositoriesSymbolConnectionResolver) Errors() []*repositorySymbolsErrorResolver {
+	return r.errors
+}
+
+// repositorySymbolsErrorResolver is a failure to list (some of) the symbols of
+// a repository.
+type repositorySymbolsErrorResolver struct {
+	repository string
+	err        error
+}
+
+func (r *repositorySymbolsErrorResolver) Repository() string { return r.repository }
+
+func (r *repositorySymbolsErrorResolver) Message() string { return r.err.Error() }
@@ -0,0 +1,114 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSchemaResolver_Symbols(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		switch name {
+		case "a":
+			return &types.Repo{ID: 1, Name: name}, nil
+		case "b":
+			return &types.Repo{ID: 2, Name: name}, nil
+		}
+		return nil, &errcode.Mock{Message: "repo not found", IsNotFound: true}
+	}
+	backend.Mocks.Repos.ResolveRev = func(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+		return exampleCommitSHA1, nil
+	}
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		if args.CommitID != exampleCommitSHA1 {
+			t.Errorf("got commit %q, want %q", args.CommitID, exampleCommitSHA1)
+		}
+		return []protocol.Symbol{
+			{Name: "f" + string(args.Repo), Path: "x.go", Line: 1},
+			{Name: "g" + string(args.Repo), Path: "y.go", Line: 1},
+		}, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					symbols(repositories: ["b", "missing", "a"], first: 3) {
+						nodes {
+							name
+							commit {
+								oid
+								repository {
+									name
+								}
+							}
+						}
+						limitHit
+						errors {
+							repository
+							message
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"symbols": {
+						"nodes": [
+							{"name": "fa", "commit": {"oid": "1234567890123456789012345678901234567890", "repository": {"name": "a"}}},
+							{"name": "ga", "commit": {"oid": "1234567890123456789012345678901234567890", "repository": {"name": "a"}}},
+							{"name": "fb", "commit": {"oid": "1234567890123456789012345678901234567890", "repository": {"name": "b"}}}
+						],
+						"limitHit": true,
+						"errors": [
+							{"repository": "missing", "message": "repo not found"}
+						]
+					}
+				}
+			`,
+		},
+	})
+}
+
+func TestSchemaResolver_Symbols_cancelled(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{DebugSearchSymbolsParallelism: 1}})
+	defer conf.Mock(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var looked []api.RepoName
+	backend.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		looked = append(looked, name)
+		// The client goes away while the first repository is processed.
+		cancel()
+		return nil, ctx.Err()
+	}
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		t.Error("unexpected symbols service call")
+		return nil, nil
+	}
+
+	_, err := (&schemaResolver{}).Symbols(ctx, &repositoriesSymbolsArgs{Repositories: []string{"a", "b", "c"}})
+	if err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if len(looked) != 1 {
+		t.Errorf("got repositories %v looked up, want only the first", looked)
+	}
+}
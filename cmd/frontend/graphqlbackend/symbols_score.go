@@ -0,0 +1,93 @@
+package graphqlbackend
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// scoreSymbolName returns how relevant a symbol named name is to the query
+// text, from 0 (not similar) to 100 (an exact match). The score is coarse so
+// that symbols that match in the same way (e.g., all prefix matches) tie, and
+// are ordered by location instead.
+//
+// The score only considers the query as text, so the regular expression
+// syntax in a regexp query counts as characters to match.
+func scoreSymbolName(name, query string) int32 {
+	if query == "" {
+		return 0
+	}
+	if name == query {
+		return 100
+	}
+	lowerName, lowerQuery := strings.ToLower(name), strings.ToLower(query)
+	switch {
+	case lowerName == lowerQuery:
+		return 90
+	case strings.HasPrefix(lowerName, lowerQuery):
+		return 80
+	}
+	if i := strings.Index(lowerName, lowerQuery); i >= 0 {
+		// The offset is in the lowercased name, so it only applies to name if lowercasing didn't
+		// change the length of any characters.
+		if len(lowerName) == len(name) && isWordStart(name, i) {
+			return 70
+		}
+		return 60
+	}
+	if gaps, ok := subsequenceGaps(lowerName, lowerQuery); ok {
+		// Prefer subsequences whose characters are close together.
+		if gaps > 30 {
+			gaps = 30
+		}
+		return 40 - int32(gaps)
+	}
+	return 0
+}
+
+// isWordStart reports whether the byte offset i in name is the start of a word
+// (e.g., "Reader" in "bufReader" or "buf_reader").
+func isWordStart(name string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(name[:i])
+	cur, _ := utf8.DecodeRuneInString(name[i:])
+	return (!unicode.IsLetter(prev) && !unicode.IsDigit(prev)) || (unicode.IsLower(prev) && unicode.IsUpper(cur))
+}
+
+// subsequenceGaps reports whether the characters of query occur in name in
+// order and, if so, how many characters of name lie between the first and last
+// of them that aren't part of query.
+func subsequenceGaps(name, query string) (gaps int, ok bool) {
+	for n, q := range []rune(query) {
+		i := strings.IndexRune(name, q)
+		if i < 0 {
+			return 0, false
+		}
+		if n > 0 {
+			gaps += i
+		}
+		name = name[i+utf8.RuneLen(q):]
+	}
+	return gaps, true
+}
+
+// scoreSymbols sets the score of each of the symbols for the query text. If the
+// query is a qualified name, symbols are scored by the better of their name and
+// qualified name.
+func scoreSymbols(symbols []*symbolResolver, query string, qualified bool) {
+	for _, symbol := range symbols {
+		score := scoreSymbolName(symbol.symbol.Name, query)
+		if qualified {
+			if s := scoreSymbolName(symbol.languageQualifiedName(), query); s > score {
+				score = s
+			}
+		}
+		symbol.score = &score
+	}
+}
+
+// Score returns how relevant the symbol is to the query argument, from 0 to 100 (see
+// scoreSymbolName), or nil if there was no query.
+func (r *symbolResolver) Score() *int32 { return r.score }
@@ -0,0 +1,82 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+)
+
+func TestScoreSymbolName(t *testing.T) {
+	tests := []struct {
+		name, query string
+		want        int32
+	}{
+		{name: "Reader", query: "Reader", want: 100},
+		{name: "Reader", query: "reader", want: 90},
+		{name: "ReaderAt", query: "reader", want: 80},
+		{name: "bufReader", query: "reader", want: 70},
+		{name: "buf_reader", query: "reader", want: 70},
+		{name: "threader", query: "reader", want: 60},
+		{name: "newSymbolResolver", query: "nsr", want: 40 - 7},
+		{name: "Writer", query: "reader", want: 0},
+		{name: "Reader", query: "", want: 0},
+	}
+	for _, test := range tests {
+		if got := scoreSymbolName(test.name, test.query); got != test.want {
+			t.Errorf("%q for query %q: got %d, want %d", test.name, test.query, got, test.want)
+		}
+	}
+}
+
+func TestSymbolConnectionResolver_orderByRelevance(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "threader", Path: "a.go", Line: 1},
+			{Name: "ReaderAt", Path: "a.go", Line: 2},
+			{Name: "reader", Path: "b.go", Line: 1},
+			{Name: "Readers", Path: "c.go", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	ctx := context.Background()
+	query, orderBy, first := "reader", "RELEVANCE", int32(2)
+	var (
+		pages [][]string
+		after *string
+	)
+	for len(pages) < 5 {
+		conn, err := commit.Symbols(ctx, &symbolsArgs{
+			ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+			Query:          &query,
+			RegExp:         new(bool),
+			OrderBy:        &orderBy,
+			After:          after,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes, _ := conn.Nodes(ctx)
+		pages = append(pages, symbolNames(nodes))
+		pageInfo, _ := conn.PageInfo(ctx)
+		if !pageInfo.HasNextPage() {
+			break
+		}
+		after = pageInfo.EndCursor()
+	}
+	if want := [][]string{{"reader", "ReaderAt"}, {"Readers", "threader"}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("got pages %v, want %v", pages, want)
+	}
+}
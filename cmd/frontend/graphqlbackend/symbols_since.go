@@ -0,0 +1,136 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+const (
+	// maxSymbolsSinceFirst is the maximum value of first when the since
+	// argument is given, because the file that defines every symbol that is
+	// considered must be blamed.
+	maxSymbolsSinceFirst = 100
+
+	// maxSymbolsSinceFiles is the maximum number of files whose symbols can be
+	// filtered by the since argument in one request, because each file must be
+	// blamed.
+	maxSymbolsSinceFiles = 100
+)
+
+// symbolsBlameCache caches the blame of files (keyed by repository, commit, and
+// path) for the since argument. Blame at a commit never changes, so entries
+// don't expire.
+var (
+	symbolsBlameCacheMu sync.Mutex
+	symbolsBlameCache   = lru.New(200)
+)
+
+// validateSymbolsSince returns an error if the since argument is given with a
+// first value above maxSymbolsSinceFirst.
+func validateSymbolsSince(args *symbolsArgs) error {
+	if args.Since == nil || *args.Since == "" {
+		return nil
+	}
+	if limitOrDefault(args.First) > maxSymbolsSinceFirst {
+		return fmt.Errorf("symbols: 'first' must be at most %d when 'since' is given", maxSymbolsSinceFirst)
+	}
+	return nil
+}
+
+// newSymbolsSince returns the time given by the since argument: an RFC 3339
+// date, or a revision of the commit's repository, whose author date is used
+// (because blame reports the author dates of the lines it is compared with).
+func newSymbolsSince(ctx context.Context, commit *GitCommitResolver, since *string) (*time.Time, error) {
+	if since == nil || *since == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, *since); err == nil {
+		return &t, nil
+	}
+	repo := gitserver.Repo{Name: commit.repo.repo.Name}
+	id, err := git.ResolveRevision(ctx, repo, nil, *since, nil)
+	if err != nil {
+		return nil, fmt.Errorf("since: %q is neither an RFC 3339 date nor a revision: %s", *since, err)
+	}
+	sinceCommit, err := git.GetCommit(ctx, repo, nil, id)
+	if err != nil {
+		return nil, err
+	}
+	return &sinceCommit.Author.Date, nil
+}
+
+// filterSymbolsSince returns the symbols whose start lines were last changed
+// (according to the author dates of git blame at the commit) after since.
+// Symbols that can't be blamed (those of directories, and those whose lines
+// are past the end of their files) are kept, because it is unknown when they
+// changed. Each file is blamed once (and the blame is cached), but that is
+// still expensive, so the number of files is limited to maxSymbolsSinceFiles.
+func filterSymbolsSince(ctx context.Context, commit *GitCommitResolver, symbols []*symbolResolver, since time.Time) ([]*symbolResolver, error) {
+	hunksByPath := map[string][]*git.Hunk{}
+	for _, symbol := range symbols {
+		if strings.HasSuffix(symbol.symbol.Path, "/") {
+			continue // directories can't be blamed
+		}
+		hunksByPath[symbol.uri.Fragment] = nil
+	}
+	if len(hunksByPath) > maxSymbolsSinceFiles {
+		return nil, fmt.Errorf("since: the symbols are defined in more than %d files; narrow the symbols with the query or path arguments", maxSymbolsSinceFiles)
+	}
+	for path := range hunksByPath {
+		hunks, err := blameSymbolFile(ctx, commit, path)
+		if err != nil {
+			return nil, err
+		}
+		hunksByPath[path] = hunks
+	}
+
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if symbolChangedSince(symbol, hunksByPath[symbol.uri.Fragment], since) {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered, nil
+}
+
+// symbolChangedSince reports whether the blame hunk of the symbol's start line
+// is dated after since, or if the symbol's start line isn't in any hunk.
+func symbolChangedSince(symbol *symbolResolver, hunks []*git.Hunk, since time.Time) bool {
+	line := symbol.location.lspRange.Start.Line + 1 // blame lines are 1-indexed
+	for _, hunk := range hunks {
+		if hunk.StartLine <= line && line < hunk.EndLine {
+			return hunk.Author.Date.After(since)
+		}
+	}
+	return true
+}
+
+// blameSymbolFile returns the blame of the file at path at the commit.
+func blameSymbolFile(ctx context.Context, commit *GitCommitResolver, path string) ([]*git.Hunk, error) {
+	key := string(commit.repo.repo.Name) + "@" + string(commit.oid) + ":" + path
+	symbolsBlameCacheMu.Lock()
+	cached, ok := symbolsBlameCache.Get(key)
+	symbolsBlameCacheMu.Unlock()
+	if ok {
+		return cached.([]*git.Hunk), nil
+	}
+
+	hunks, err := git.BlameFile(ctx, gitserver.Repo{Name: commit.repo.repo.Name}, path, &git.BlameOptions{
+		NewestCommit: api.CommitID(commit.oid),
+	})
+	if err != nil {
+		return nil, err
+	}
+	symbolsBlameCacheMu.Lock()
+	symbolsBlameCache.Add(key, hunks)
+	symbolsBlameCacheMu.Unlock()
+	return hunks, nil
+}
@@ -0,0 +1,88 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestComputeSymbols_since(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	defer git.ResetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "old", Path: "a.go", Line: 1},
+			{Name: "new", Path: "a.go", Line: 3},
+			{Name: "other", Path: "b.go", Line: 1},
+			// Directories and lines past the end of the file can't be blamed, so they are kept.
+			{Name: "pkg", Path: "pkg/", Line: 1},
+			{Name: "stale", Path: "b.go", Line: 20},
+		}, nil
+	}
+	day := func(d int) time.Time { return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC) }
+	blames := map[string]int{}
+	git.Mocks.BlameFile = func(path string, opt *git.BlameOptions) ([]*git.Hunk, error) {
+		blames[path]++
+		if opt.NewestCommit != "c1" {
+			t.Errorf("got blame at %q, want c1", opt.NewestCommit)
+		}
+		if path == "b.go" {
+			return []*git.Hunk{{StartLine: 1, EndLine: 10, Author: git.Signature{Date: day(1)}}}, nil
+		}
+		return []*git.Hunk{
+			{StartLine: 1, EndLine: 3, Author: git.Signature{Date: day(1)}},
+			{StartLine: 3, EndLine: 5, Author: git.Signature{Date: day(3)}},
+		}, nil
+	}
+	git.Mocks.ResolveRevision = func(spec string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		return "c0", nil
+	}
+	git.Mocks.GetCommit = func(id api.CommitID) (*git.Commit, error) {
+		// Blame reports author dates, so the author date (not the committer date) is used.
+		return &git.Commit{ID: id, Author: git.Signature{Date: day(2)}, Committer: &git.Signature{Date: day(0)}}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	for _, since := range []string{"2020-01-02T00:00:00Z", "HEAD~1"} {
+		since := since
+		symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Since: &since})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := symbolNames(symbols), []string{"new", "stale", "pkg"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("since %q: got %v, want %v", since, got, want)
+		}
+	}
+	// The blame of each file is cached.
+	if want := map[string]int{"a.go": 1, "b.go": 1}; !reflect.DeepEqual(blames, want) {
+		t.Errorf("got blames %v, want %v", blames, want)
+	}
+}
+
+func TestSymbolConnection_sinceFirst(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	since, first := "2020-01-02T00:00:00Z", int32(maxSymbolsSinceFirst+1)
+	if _, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, Since: &since}); err == nil {
+		t.Error("got nil error for a first value above the since limit")
+	}
+}
@@ -0,0 +1,61 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxSymbolSnippetContextLines is the maximum value of the Symbol.snippet
+// contextLines argument.
+const maxSymbolSnippetContextLines = 10
+
+// Snippet returns the source lines of the symbol's range, with args.ContextLines
+// lines of context before and after it. The file content is shared by the
+// symbols of a result set that are defined in the same file (see symbolFile),
+// so listing snippets of many symbols in a file reads it once.
+func (r *symbolResolver) Snippet(ctx context.Context, args *struct{ ContextLines *int32 }) (*string, error) {
+	contextLines := 0
+	if args.ContextLines != nil {
+		contextLines = int(*args.ContextLines)
+	}
+	if contextLines < 0 || contextLines > maxSymbolSnippetContextLines {
+		return nil, fmt.Errorf("snippet: 'contextLines' must be between 0 and %d", maxSymbolSnippetContextLines)
+	}
+	content, err := r.symbolFileContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rng := r.location.lspRange
+	snippet, ok := symbolSnippet(content, rng.Start.Line, rng.End.Line, contextLines)
+	if !ok {
+		return nil, nil
+	}
+	return &snippet, nil
+}
+
+// symbolSnippet returns the (zero-based, inclusive) lines start through end of
+// content, extended by contextLines lines before and after (as far as the
+// content allows). It reports false if start is not a line of content.
+func symbolSnippet(content string, start, end, contextLines int) (string, bool) {
+	lines := strings.SplitAfter(content, "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		// Don't count the empty "line" after a trailing newline.
+		lines = lines[:n-1]
+	}
+	if start < 0 || start >= len(lines) {
+		return "", false
+	}
+	if end < start {
+		end = start
+	}
+	start -= contextLines
+	if start < 0 {
+		start = 0
+	}
+	end += contextLines
+	if end >= len(lines) {
+		end = len(lines) - 1
+	}
+	return strings.TrimSuffix(strings.Join(lines[start:end+1], ""), "\n"), true
+}
@@ -0,0 +1,43 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSymbolSnippet(t *testing.T) {
+	content := "package a\n\nfunc alpha() {\n}\n\nfunc beta() {}\n"
+	tests := []struct {
+		name         string
+		start, end   int
+		contextLines int
+		want         string
+		wantOK       bool
+	}{
+		{name: "one line", start: 5, end: 5, want: "func beta() {}", wantOK: true},
+		{name: "range", start: 2, end: 3, want: "func alpha() {\n}", wantOK: true},
+		{name: "context", start: 2, end: 2, contextLines: 1, want: "\nfunc alpha() {\n}", wantOK: true},
+		{name: "context clamped", start: 0, end: 0, contextLines: 10, want: content[:len(content)-1], wantOK: true},
+		{name: "out of range", start: 6, end: 6, wantOK: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := symbolSnippet(content, test.start, test.end, test.contextLines)
+			if ok != test.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, test.wantOK)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSymbolResolver_Snippet_contextLines(t *testing.T) {
+	r := &symbolResolver{}
+	for _, n := range []int32{-1, maxSymbolSnippetContextLines + 1} {
+		if _, err := r.Snippet(context.Background(), &struct{ ContextLines *int32 }{ContextLines: &n}); err == nil {
+			t.Errorf("contextLines %d: got nil error, want error", n)
+		}
+	}
+}
@@ -0,0 +1,2420 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/zoekt"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	symbolsclient "github.com/sourcegraph/sourcegraph/internal/symbols"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func symbolNames(symbols []*symbolResolver) []string {
+	names := make([]string, len(symbols))
+	for i, s := range symbols {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// mockPagedListTags returns a mock of backend.Symbols.ListTags that pages
+// through symbols like the symbols service does: it skips args.Offset symbols
+// and returns at most args.First of the rest, and never more than
+// protocol.MaxFirst.
+func mockPagedListTags(symbols []protocol.Symbol) func(context.Context, search.SymbolsParameters) ([]protocol.Symbol, error) {
+	return func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		first := args.First
+		if first < 0 || first > protocol.MaxFirst {
+			first = protocol.MaxFirst
+		}
+		if args.Offset >= len(symbols) {
+			return nil, nil
+		}
+		page := symbols[args.Offset:]
+		if len(page) > first {
+			page = page[:first]
+		}
+		return page, nil
+	}
+}
+
+// manySymbols returns n symbols of alternating kinds in a.go, one per line.
+func manySymbols(n int) []protocol.Symbol {
+	symbols := make([]protocol.Symbol, n)
+	for i := range symbols {
+		kind := "function"
+		if i%2 == 1 {
+			kind = "class"
+		}
+		symbols[i] = protocol.Symbol{Name: fmt.Sprintf("s%d", i), Path: "a.go", Line: i + 1, Kind: kind}
+	}
+	return symbols
+}
+
+func TestComputeSymbols_deterministicOrder(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	tags := []protocol.Symbol{
+		{Name: "d", Path: "b.go", Line: 1},
+		{Name: "b", Path: "a.go", Line: 2},
+		{Name: "a", Path: "a.go", Line: 2},
+		{Name: "c", Path: "a.go", Line: 3},
+		{Name: "e", Path: "a.go", Line: 1},
+	}
+	calls := 0
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		calls++
+		// Return the symbols in a different order on every call.
+		shuffled := make([]protocol.Symbol, len(tags))
+		for i := range tags {
+			shuffled[i] = tags[(i+calls)%len(tags)]
+		}
+		return shuffled, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	want := []string{"e", "a", "b", "c", "d"}
+	for i := 0; i < 2; i++ {
+		symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := symbolNames(symbols); !reflect.DeepEqual(got, want) {
+			t.Errorf("call %d: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestComputeSymbols_kinds(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "f", Path: "a.go", Line: 1, Kind: "function"},
+			{Name: "C", Path: "a.go", Line: 2, Kind: "class"},
+			{Name: "v", Path: "a.go", Line: 3, Kind: "variable"},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+
+	// The mock ignores the kinds sent to it (like older symbols services do), so this also
+	// tests that the symbols are filtered by kind afterward.
+	kinds := []string{"FUNCTION", "CLASS"}
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Kinds: &kinds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"f", "C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	invalid := []string{"NOTAKIND"}
+	if _, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Kinds: &invalid}); err == nil {
+		t.Error("got nil error for invalid kind")
+	}
+}
+
+func TestComputeSymbols_kindsSentToSymbolsService(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		SearchSymbolsKindOverrides: map[string]map[string]string{"go": {"Type": "STRUCT"}},
+	}})
+	defer conf.Mock(nil)
+
+	var gotKinds []string
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotKinds = args.Kinds
+		return nil, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		kinds []string
+		want  []string
+	}{
+		{kinds: []string{"METHOD", "STRUCT"}, want: []string{"method", "methodspec", "struct", "type"}},
+		// Symbols of unknown kinds can't be selected by their ctags kinds.
+		{kinds: []string{"METHOD", "UNKNOWN"}, want: nil},
+		{kinds: nil, want: nil},
+	}
+	for _, test := range tests {
+		kinds := test.kinds
+		if _, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Kinds: &kinds}); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(gotKinds, test.want) {
+			t.Errorf("%v: got ctags kinds %v, want %v", test.kinds, gotKinds, test.want)
+		}
+	}
+}
+
+func TestComputeSymbols_definitionsOnly(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "fmt", Kind: "packageName", Path: "a.go", Line: 3},
+			{Name: "f", Kind: "func", Path: "a.go", Line: 5},
+			{Name: "x", Kind: "import", Path: "b.py", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	no := false
+	tests := []struct {
+		name           string
+		referenceKinds []string
+		args           *symbolsArgs
+		want           []string
+	}{
+		{name: "default", args: &symbolsArgs{}, want: []string{"f", "x"}},
+		{name: "disabled", args: &symbolsArgs{DefinitionsOnly: &no}, want: []string{"fmt", "f", "x"}},
+		{name: "site config", referenceKinds: []string{"IMPORT"}, args: &symbolsArgs{}, want: []string{"fmt", "f"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchSymbolsReferenceKinds: test.referenceKinds}})
+			defer conf.Mock(nil)
+			symbols, _, err := computeSymbols(context.Background(), commit, "", test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := symbolNames(symbols); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestComputeSymbols_excludeDeclarations(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "f", Kind: "prototype", Path: "a.h", Line: 1},
+			{Name: "x", Kind: "externvar", Path: "a.h", Line: 2},
+			{Name: "f", Kind: "function", Path: "a.c", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, symbol := range symbols {
+		got = append(got, fmt.Sprintf("%s %s %s %v", symbol.uri.Fragment, symbol.symbol.Name, symbol.Kind(), symbol.IsDefinition()))
+	}
+	want := []string{"a.c f FUNCTION true", "a.h f FUNCTION false", "a.h x VARIABLE false"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	yes := true
+	symbols, _, err = computeSymbols(context.Background(), commit, "", &symbolsArgs{ExcludeDeclarations: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"f"}; !reflect.DeepEqual(got, want) || symbols[0].uri.Fragment != "a.c" {
+		t.Errorf("got %v, want only the definition of f in a.c", got)
+	}
+}
+
+func TestComputeSymbols_topLevelOnly(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	calls := 0
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		calls++
+		return []protocol.Symbol{
+			{Name: "A", Kind: "class", Path: "a.py", Line: 1},
+			{Name: "m", Kind: "member", Parent: "A", Path: "a.py", Line: 2},
+			{Name: "b", Kind: "function", Path: "a.py", Line: 4},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	topLevelOnly := true
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{TopLevelOnly: &topLevelOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"A", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := symbolNames(symbols[0].Children()), []string{"m"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got children %v, want %v", got, want)
+	}
+
+	// The symbols cached for the top-level symbols are reused for all symbols.
+	symbols, _, err = computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"A", "m", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if calls != 1 {
+		t.Errorf("got %d symbols service calls, want 1", calls)
+	}
+}
+
+func TestComputeSymbols_deduplicateByMoniker(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "x", Kind: "prototype", Language: "C", Path: "a.h", Line: 1},
+			{Name: "x", Kind: "function", Language: "C", Path: "a.c", Line: 1},
+			{Name: "x", Kind: "prototype", Language: "C", Path: "b.h", Line: 1},
+			{Name: "x", Kind: "function", Language: "C", Parent: "y", Path: "b.c", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	yes := true
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{DeduplicateByMoniker: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, symbol := range symbols {
+		got = append(got, fmt.Sprintf("%s %s %d", symbol.uri.Fragment, symbol.symbol.Name, symbol.DuplicateCount()))
+	}
+	want := []string{"a.c x 2", "b.c x 0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The counts are kept when the symbols are cached.
+	symbols, _, err = computeSymbols(context.Background(), commit, "", &symbolsArgs{DeduplicateByMoniker: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 2 || symbols[0].DuplicateCount() != 2 {
+		t.Errorf("got %v from the cache, want the counts to be kept", symbolNames(symbols))
+	}
+
+	// Without the argument, symbols in different files are not collapsed.
+	symbols, _, err = computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 4 {
+		t.Errorf("got %d symbols, want 4", len(symbols))
+	}
+}
+
+func TestIndexedSymbolsFallback(t *testing.T) {
+	repo := &zoekt.Repository{Branches: []zoekt.RepositoryBranch{{Name: "HEAD", Version: "c1"}, {Name: "dev", Version: "c2"}}}
+	tests := []struct {
+		repo   *zoekt.Repository
+		commit string
+		want   string
+		wantOK bool
+	}{
+		{repo: repo, commit: "c1", wantOK: false},
+		{repo: repo, commit: "c2", wantOK: false},
+		{repo: repo, commit: "c3", want: "c1", wantOK: true},
+		{repo: nil, commit: "c3", wantOK: false},
+		{repo: &zoekt.Repository{}, commit: "c3", wantOK: false},
+	}
+	for _, test := range tests {
+		got, ok := indexedSymbolsFallback(test.repo, test.commit)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("%s: got (%q, %v), want (%q, %v)", test.commit, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestToSymbolResolver_language(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	tests := []struct {
+		symbol protocol.Symbol
+		lang   string
+		want   string
+	}{
+		{protocol.Symbol{Name: "a", Path: "a.go"}, "go", "go"},
+		{protocol.Symbol{Name: "a", Path: "a.py"}, "", "python"},
+		{protocol.Symbol{Name: "a", Path: "a"}, "", ""},
+	}
+	for _, test := range tests {
+		if got := toSymbolResolver(test.symbol, baseURI, test.lang, nil).Language(); got != test.want {
+			t.Errorf("%s: got language %q, want %q", test.symbol.Path, got, test.want)
+		}
+	}
+}
+
+func TestToSymbolResolver_directory(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		path     string
+		wantPath string
+		wantDir  bool
+	}{
+		{path: "pkg/mux/", wantPath: "pkg/mux", wantDir: true},
+		{path: "pkg/mux/mux.go", wantPath: "pkg/mux/mux.go", wantDir: false},
+	}
+	for _, test := range tests {
+		r := toSymbolResolver(protocol.Symbol{Name: "mux", Kind: "package", Path: test.path, Line: 1}, baseURI, "go", commit)
+		entry := r.Location().Resource()
+		if got := entry.Path(); got != test.wantPath {
+			t.Errorf("%q: got path %q, want %q", test.path, got, test.wantPath)
+		}
+		if got := entry.IsDirectory(); got != test.wantDir {
+			t.Errorf("%q: got isDirectory %v, want %v", test.path, got, test.wantDir)
+		}
+	}
+}
+
+func TestComputeSymbols_timeout(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchSymbolsTimeout: "10ms"}})
+	defer conf.Mock(nil)
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		// Simulate a symbols service that hangs until the request is abandoned.
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	start := time.Now()
+	_, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+	if err == nil {
+		t.Fatal("got nil error, want timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("computeSymbols took %s, want it to give up after the configured timeout", elapsed)
+	}
+}
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "symbols service unavailable" }
+func (temporaryError) Temporary() bool { return true }
+
+func TestComputeSymbols_retry(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchSymbolsRetryBackoff: "1ms", SearchSymbolsCacheTTL: "0s"}})
+	defer conf.Mock(nil)
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		name      string
+		errs      []error // the errors of successive calls (nil after the last one)
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "temporary errors", errs: []error{temporaryError{}, temporaryError{}}, wantCalls: 3},
+		{name: "too many temporary errors", errs: []error{temporaryError{}, temporaryError{}, temporaryError{}}, wantCalls: 3, wantErr: true},
+		{name: "permanent error", errs: []error{errors.New("bad request")}, wantCalls: 1, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+				calls++
+				if calls <= len(test.errs) {
+					return nil, test.errs[calls-1]
+				}
+				return []protocol.Symbol{{Name: "a", Path: "a.go", Line: 1}}, nil
+			}
+			_, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error %v", err, test.wantErr)
+			}
+			if calls != test.wantCalls {
+				t.Errorf("got %d calls, want %d", calls, test.wantCalls)
+			}
+		})
+	}
+}
+
+func TestComputeSymbols_deduplicate(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "f", Path: "a.go", Line: 1},
+			{Name: "f", Path: "a.go", Line: 1, Kind: "function", Signature: "()"},
+			{Name: "f", Path: "b.go", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 2 {
+		t.Fatalf("got %d symbols, want 2", len(symbols))
+	}
+	if got := symbols[0].Kind(); got != "FUNCTION" {
+		t.Errorf("got kind %q, want the symbol with more metadata to be kept", got)
+	}
+
+	deduplicate := false
+	symbols, _, err = computeSymbols(context.Background(), commit, "", &symbolsArgs{Deduplicate: &deduplicate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 3 {
+		t.Errorf("got %d symbols, want 3 when deduplication is disabled", len(symbols))
+	}
+}
+
+func TestSymbolConnectionResolver_TotalCount_moreThanSymbolsServiceLimit(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = mockPagedListTags(manySymbols(1234))
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	first := int32(10)
+	conn, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := conn.TotalCount(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1234 {
+		t.Errorf("got total count %d, want 1234", count)
+	}
+}
+
+func TestSymbolConnectionResolver_TotalCount(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var tags []protocol.Symbol
+	for i := 0; i < 5; i++ {
+		tags = append(tags, protocol.Symbol{Name: "s", Path: "a.go", Line: i + 1, Kind: "function"})
+	}
+	tags = append(tags, protocol.Symbol{Name: "C", Path: "a.go", Line: 10, Kind: "class"})
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		if args.First < len(tags) {
+			return tags[:args.First], nil
+		}
+		return tags, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	first := int32(2)
+	kinds := []string{"FUNCTION"}
+	conn, err := commit.Symbols(context.Background(), &symbolsArgs{
+		ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+		Kinds:          &kinds,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := conn.TotalCount(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Errorf("got total count %d, want 5", count)
+	}
+}
+
+func TestSymbolConnectionResolver_KindCounts(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	tags := []protocol.Symbol{
+		{Name: "a", Path: "a.go", Line: 1, Kind: "function"},
+		{Name: "B", Path: "a.go", Line: 2, Kind: "class"},
+		{Name: "c", Path: "a.go", Line: 3, Kind: "function"},
+		{Name: "D", Path: "a.go", Line: 4, Kind: "interface"},
+		{Name: "E", Path: "b.go", Line: 1, Kind: "class"},
+		{Name: "f", Path: "b.go", Line: 2, Kind: "function"},
+	}
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		if args.First < len(tags) {
+			return tags[:args.First], nil
+		}
+		return tags, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	first := int32(1)
+	conn, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts, err := conn.KindCounts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range counts {
+		got = append(got, fmt.Sprintf("%s=%d", c.Kind(), c.Count()))
+	}
+	if want := []string{"FUNCTION=3", "CLASS=2", "INTERFACE=1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	kinds, err := conn.KindsPresent(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"CLASS", "FUNCTION", "INTERFACE"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("got kinds present %v, want %v", kinds, want)
+	}
+}
+
+func TestSymbolConnectionResolver_KindCounts_limitHit(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	tests := []struct {
+		symbols      int
+		want         []string
+		wantLimitHit bool
+	}{
+		// More symbols than the symbols service returns per request.
+		{symbols: 1234, want: []string{"CLASS=617", "FUNCTION=617"}},
+		{symbols: symbolsCountLimit + 5, want: []string{"CLASS=5000", "FUNCTION=5000"}, wantLimitHit: true},
+	}
+	for _, test := range tests {
+		backend.Mocks.Symbols.ListTags = mockPagedListTags(manySymbols(test.symbols))
+		commit := &GitCommitResolver{
+			repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+			oid:  GitObjectID(fmt.Sprintf("c%d", test.symbols)),
+		}
+		first := int32(10)
+		conn, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts, err := conn.KindCounts(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, c := range counts {
+			got = append(got, fmt.Sprintf("%s=%d", c.Kind(), c.Count()))
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%d symbols: got %v, want %v", test.symbols, got, test.want)
+		}
+		limitHit, err := conn.CountsLimitHit(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if limitHit != test.wantLimitHit {
+			t.Errorf("%d symbols: got countsLimitHit %v, want %v", test.symbols, limitHit, test.wantLimitHit)
+		}
+	}
+}
+
+// TestSymbolConnectionResolver_KindsPresent_pastSymbolsServiceLimit tests that
+// kindsPresent includes kinds that only occur after the first page of the
+// symbols service (which returns at most protocol.MaxFirst symbols per request).
+func TestSymbolConnectionResolver_KindsPresent_pastSymbolsServiceLimit(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	symbols := manySymbols(1234)
+	symbols[1000].Kind = "interface"
+	backend.Mocks.Symbols.ListTags = mockPagedListTags(symbols)
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	first := int32(10)
+	conn, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds, err := conn.KindsPresent(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"CLASS", "FUNCTION", "INTERFACE"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("got kinds present %v, want %v", kinds, want)
+	}
+}
+
+func TestSymbolConnectionResolver_cursors(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "b", Path: "a.go", Line: 2},
+			{Name: "c", Path: "a.go", Line: 3},
+			{Name: "d", Path: "b.go", Line: 1},
+			{Name: "e", Path: "b.go", Line: 2},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	ctx := context.Background()
+	first := int32(2)
+	symbolsPage := func(after, before *string) ([]string, *graphqlutil.PageInfo) {
+		t.Helper()
+		conn, err := commit.Symbols(ctx, &symbolsArgs{
+			ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+			After:          after,
+			Before:         before,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes, _ := conn.Nodes(ctx)
+		pageInfo, _ := conn.PageInfo(ctx)
+		return symbolNames(nodes), pageInfo
+	}
+
+	var pages [][]string
+	var after *string
+	for {
+		names, pageInfo := symbolsPage(after, nil)
+		pages = append(pages, names)
+		if !pageInfo.HasNextPage() {
+			break
+		}
+		after = pageInfo.EndCursor()
+	}
+	if want := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}; !reflect.DeepEqual(pages, want) {
+		t.Fatalf("got forward pages %v, want %v", pages, want)
+	}
+
+	_, lastPageInfo := symbolsPage(after, nil)
+	names, pageInfo := symbolsPage(nil, lastPageInfo.StartCursor())
+	if want := []string{"c", "d"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got backward page %v, want %v", names, want)
+	}
+	if !pageInfo.HasPreviousPage() || !pageInfo.HasNextPage() {
+		t.Errorf("got hasPreviousPage=%v hasNextPage=%v, want both true", pageInfo.HasPreviousPage(), pageInfo.HasNextPage())
+	}
+}
+
+func TestSymbolConnectionResolver_hasNextPage(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	first := int32(3)
+	tests := []struct {
+		name  string
+		tags  []protocol.Symbol
+		kinds []string
+		want  bool
+	}{
+		{
+			name: "fewer than first",
+			tags: []protocol.Symbol{{Name: "a", Path: "a.go", Kind: "function"}, {Name: "b", Path: "b.go", Kind: "function"}},
+			want: false,
+		},
+		{
+			// All of the symbols that the page is taken from were filtered, so there are no more.
+			name:  "filtered below first",
+			tags:  []protocol.Symbol{{Name: "a", Kind: "function"}, {Name: "b", Kind: "class"}, {Name: "c", Kind: "class"}, {Name: "d", Kind: "class"}},
+			kinds: []string{"FUNCTION"},
+			want:  false,
+		},
+		{
+			// The source returned more symbols than the window that pages are taken from, so
+			// there may be more matching symbols after it.
+			name:  "source hit window limit but filtered below first",
+			tags:  manySymbols(symbolsCountLimit + 1),
+			kinds: []string{"INTERFACE"},
+			want:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+				return test.tags, nil
+			}
+			args := &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}}
+			if test.kinds != nil {
+				args.Kinds = &test.kinds
+			}
+			conn, err := commit.Symbols(context.Background(), args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pageInfo, _ := conn.PageInfo(context.Background())
+			if got := pageInfo.HasNextPage(); got != test.want {
+				t.Errorf("got hasNextPage %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSymbolConnectionResolver_offset(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotFirst int
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotFirst = args.First
+		symbols := []protocol.Symbol{
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "b", Path: "a.go", Line: 2},
+			{Name: "c", Path: "a.go", Line: 3},
+			{Name: "d", Path: "a.go", Line: 4},
+			{Name: "e", Path: "a.go", Line: 5},
+		}
+		if len(symbols) > args.First {
+			symbols = symbols[:args.First]
+		}
+		return symbols, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		offset, first        int32
+		want                 []string
+		hasPrevious, hasNext bool
+	}{
+		{offset: 0, first: 2, want: []string{"a", "b"}, hasPrevious: false, hasNext: true},
+		{offset: 2, first: 2, want: []string{"c", "d"}, hasPrevious: true, hasNext: true},
+		{offset: 4, first: 2, want: []string{"e"}, hasPrevious: true, hasNext: false},
+		{offset: 9, first: 2, want: []string{}, hasPrevious: true, hasNext: false},
+	}
+	for _, test := range tests {
+		offset, first := test.offset, test.first
+		conn, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, Offset: &offset})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Every page is taken from the same window of symbols, which is fetched a page of the
+		// symbols service at a time.
+		if want := protocol.MaxFirst; gotFirst != want {
+			t.Errorf("offset %d: got backend limit %d, want %d", offset, gotFirst, want)
+		}
+		nodes, _ := conn.Nodes(context.Background())
+		if got := symbolNames(nodes); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("offset %d: got %v, want %v", offset, got, test.want)
+		}
+		pageInfo, _ := conn.PageInfo(context.Background())
+		if pageInfo.HasPreviousPage() != test.hasPrevious || pageInfo.HasNextPage() != test.hasNext {
+			t.Errorf("offset %d: got hasPreviousPage %v and hasNextPage %v, want %v and %v", offset, pageInfo.HasPreviousPage(), pageInfo.HasNextPage(), test.hasPrevious, test.hasNext)
+		}
+	}
+
+	offset, after := int32(1), marshalSymbolCursor(&symbolCursor{Path: "a.go"})
+	if _, err := commit.Symbols(context.Background(), &symbolsArgs{Offset: &offset, After: &after}); err == nil {
+		t.Error("got nil error for offset with a cursor")
+	}
+	negative := int32(-1)
+	if _, err := commit.Symbols(context.Background(), &symbolsArgs{Offset: &negative}); err == nil {
+		t.Error("got nil error for negative offset")
+	}
+}
+
+// TestSymbolConnectionResolver_offsetUnsortedSource tests that paging with
+// offsets visits every symbol once, in order, when the symbols source returns
+// the symbols in a different order.
+func TestSymbolConnectionResolver_offsetUnsortedSource(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	backend.Mocks.Symbols.ListTags = unsortedListTags(
+		protocol.Symbol{Name: "c", Path: "a.go", Line: 3},
+		protocol.Symbol{Name: "b", Path: "a.go", Line: 2},
+		protocol.Symbol{Name: "a", Path: "a.go", Line: 1},
+	)
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	first := int32(1)
+	var got []string
+	for offset := int32(0); offset < 3; offset++ {
+		offset := offset
+		conn, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, Offset: &offset})
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes, _ := conn.Nodes(context.Background())
+		got = append(got, symbolNames(nodes)...)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// TestSymbolConnectionResolver_offsetPastSymbolsServiceLimit tests that an
+// offset past the symbols service's maximum page size (protocol.MaxFirst)
+// still returns the symbols at that offset.
+func TestSymbolConnectionResolver_offsetPastSymbolsServiceLimit(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	backend.Mocks.Symbols.ListTags = mockPagedListTags(manySymbols(1234))
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	offset, first := int32(600), int32(2)
+	conn, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, Offset: &offset})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, _ := conn.Nodes(context.Background())
+	if got, want := symbolNames(nodes), []string{"s600", "s601"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	pageInfo, _ := conn.PageInfo(context.Background())
+	if !pageInfo.HasPreviousPage() || !pageInfo.HasNextPage() {
+		t.Errorf("got hasPreviousPage %v and hasNextPage %v, want true and true", pageInfo.HasPreviousPage(), pageInfo.HasNextPage())
+	}
+}
+
+func TestComputeSymbols_pathPatternsCase(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		// Like the symbols service, return all symbols in files that the patterns may match.
+		return []protocol.Symbol{
+			{Name: "a", Path: "Src/a.go", Line: 1},
+			{Name: "b", Path: "src/B.go", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		caseSensitive bool
+		want          []string
+	}{
+		{caseSensitive: false, want: []string{"a"}},
+		{caseSensitive: true, want: []string{"b"}},
+	}
+	for _, test := range tests {
+		include, exclude, caseSensitive := []string{"^src/"}, []string{`b\.go$`}, test.caseSensitive
+		if test.caseSensitive {
+			exclude = []string{`a\.go$`}
+		}
+		symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{IncludePatterns: &include, ExcludePatterns: &exclude, CaseSensitive: &caseSensitive})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := symbolNames(symbols); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("caseSensitive %v: got %v, want %v", test.caseSensitive, got, test.want)
+		}
+	}
+}
+
+func TestComputeSymbols_regExp(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotQuery string
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotQuery = args.Query
+		return []protocol.Symbol{
+			{Name: "a.b", Path: "a.go", Line: 1},
+			{Name: "axb", Path: "a.go", Line: 2},
+			{Name: "Ab", Path: "a.go", Line: 3},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	yes, no := true, false
+	tests := []struct {
+		query     string
+		regExp    *bool
+		wantQuery string
+		want      []string
+	}{
+		// Unless the query is explicitly a regular expression, "a.b" is also a qualified name, so
+		// the backends are asked for symbols named "b", too.
+		{query: "a.b", regExp: nil, wantQuery: "a.b|b", want: []string{"a.b", "axb"}},
+		{query: "a.b", regExp: &yes, wantQuery: "a.b", want: []string{"a.b", "axb"}},
+		{query: "a.b", regExp: &no, wantQuery: `a\.b|b`, want: []string{"a.b"}},
+		{query: "^ab$", regExp: &yes, wantQuery: "^ab$", want: []string{"Ab"}},
+	}
+	for _, test := range tests {
+		query := test.query
+		symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Query: &query, RegExp: test.regExp})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotQuery != test.wantQuery {
+			t.Errorf("%q: got backend query %q, want %q", test.query, gotQuery, test.wantQuery)
+		}
+		if got := symbolNames(symbols); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.query, got, test.want)
+		}
+	}
+
+	invalid := "("
+	if _, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Query: &invalid, RegExp: &yes}); err == nil {
+		t.Error("got nil error for invalid regular expression")
+	}
+}
+
+func TestComputeSymbols_match(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotQuery string
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotQuery = args.Query
+		return []protocol.Symbol{
+			{Name: "NewServer", Path: "a.go", Line: 1},
+			{Name: "newSymbolResolver", Path: "a.go", Line: 2},
+			{Name: "renewSession", Path: "a.go", Line: 3},
+			{Name: "n.s", Path: "a.go", Line: 4},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		match     string
+		query     string
+		wantQuery string
+		want      []string
+	}{
+		{match: "PREFIX", query: "new", wantQuery: "^new", want: []string{"NewServer", "newSymbolResolver"}},
+		{match: "SUBSTRING", query: "new", wantQuery: "new", want: []string{"NewServer", "newSymbolResolver", "renewSession"}},
+		// "n.s" is also a qualified name, so the backends are asked for symbols named "s", too.
+		{match: "SUBSTRING", query: "n.s", wantQuery: `n\.s|s`, want: []string{"n.s"}},
+		{match: "FUZZY", query: "nsr", wantQuery: "n.*s.*r", want: []string{"NewServer", "newSymbolResolver"}},
+		{match: "WHOLE_WORD", query: "new", wantQuery: "new", want: []string{"NewServer", "newSymbolResolver"}},
+		{match: "WHOLE_WORD", query: "session", wantQuery: "session", want: []string{"renewSession"}},
+	}
+	for _, test := range tests {
+		match, query := test.match, test.query
+		symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Query: &query, Match: &match})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotQuery != test.wantQuery {
+			t.Errorf("%s %q: got backend query %q, want %q", test.match, test.query, gotQuery, test.wantQuery)
+		}
+		if got := symbolNames(symbols); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s %q: got %v, want %v", test.match, test.query, got, test.want)
+		}
+	}
+
+	query, prefix, yes := "new", "PREFIX", true
+	if _, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Query: &query, Match: &prefix, RegExp: &yes}); err == nil {
+		t.Error("got nil error for a match mode with a regular expression query")
+	}
+}
+
+func TestComputeSymbols_qualifiedName(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotQuery string
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotQuery = args.Query
+		return []protocol.Symbol{
+			{Name: "Serve", Parent: "Server", Path: "a.go", Line: 1},
+			{Name: "Serve", Parent: "Client", Path: "a.go", Line: 2},
+			{Name: "push_back", Parent: "vector", Path: "a.cpp", Line: 3},
+			{Name: "to_s", Parent: "Array", Path: "a.rb", Line: 4},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		query     string
+		wantQuery string
+		want      []string
+	}{
+		{query: "Server.Serve", wantQuery: "Server.Serve|Serve", want: []string{"Serve"}},
+		{query: "vector::push", wantQuery: "vector::push|push", want: []string{"push_back"}},
+		{query: "Array#to_s", wantQuery: "Array#to_s|to_s", want: []string{"to_s"}},
+		// The separator is chosen by the symbol's language.
+		{query: "vector.push", wantQuery: "vector.push|push", want: []string{}},
+		{query: "Server.", wantQuery: "", want: []string{"Serve"}},
+	}
+	for _, test := range tests {
+		query := test.query
+		symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Query: &query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotQuery != test.wantQuery {
+			t.Errorf("%q: got backend query %q, want %q", test.query, gotQuery, test.wantQuery)
+		}
+		if got := symbolNames(symbols); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.query, got, test.want)
+		}
+		for _, symbol := range symbols {
+			if symbol.symbol.Name == "Serve" && symbol.symbol.Parent != "Server" {
+				t.Errorf("%q: got symbol in %q, want only symbols in Server", test.query, symbol.symbol.Parent)
+			}
+		}
+	}
+}
+
+func TestComputeSymbols_containerQuery(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotQuery string
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotQuery = args.Query
+		return []protocol.Symbol{
+			{Name: "Read", Parent: "Reader", Path: "a.go", Line: 1},
+			{Name: "Close", Parent: "Reader", Path: "a.go", Line: 2},
+			{Name: "Read", Parent: "readerImpl", Path: "a.go", Line: 3},
+			{Name: "Write", Parent: "Writer", Path: "a.go", Line: 4},
+			{Name: "Reader", Path: "a.go", Line: 5},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	caseSensitive := true
+	tests := []struct {
+		name string
+		args symbolsArgs
+		want []string
+	}{
+		{name: "regexp", args: symbolsArgs{ContainerQuery: strptr("^reader")}, want: []string{"Read", "Close", "Read"}},
+		{name: "case-sensitive", args: symbolsArgs{ContainerQuery: strptr("^Reader$"), CaseSensitive: &caseSensitive}, want: []string{"Read", "Close"}},
+		{name: "match mode", args: symbolsArgs{ContainerQuery: strptr("Write"), Match: strptr("PREFIX")}, want: []string{"Write"}},
+		{name: "with query", args: symbolsArgs{Query: strptr("^Read$"), ContainerQuery: strptr("Impl")}, want: []string{"Read"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			symbols, _, err := computeSymbols(context.Background(), commit, "", &test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := symbolNames(symbols); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			if test.args.Query == nil && gotQuery != "" {
+				t.Errorf("got backend query %q, want the container query not to be sent to the backend", gotQuery)
+			}
+		})
+	}
+
+	if _, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{ContainerQuery: strptr("(")}); err == nil {
+		t.Error("got nil error for invalid container query")
+	}
+}
+
+func TestComputeSymbols_caseSensitive(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotCaseSensitive bool
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotCaseSensitive = args.IsCaseSensitive
+		return []protocol.Symbol{
+			{Name: "Foo", Path: "a.go", Line: 1},
+			{Name: "foo", Path: "a.go", Line: 2},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	query := "foo"
+	yes := true
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Query: &query, CaseSensitive: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotCaseSensitive {
+		t.Error("want case sensitivity to be passed to the symbols service")
+	}
+	if got, want := symbolNames(symbols), []string{"foo"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Queries are case-insensitive by default, for both the symbols service and the filtering of
+	// its results.
+	symbols, _, err = computeSymbols(context.Background(), commit, "", &symbolsArgs{Query: &query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotCaseSensitive {
+		t.Error("want the symbols service query to be case-insensitive")
+	}
+	if got, want := symbolNames(symbols), []string{"Foo", "foo"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSymbolResolver_Children(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "C", Path: "a.py", Line: 1},
+			{Name: "m", Path: "a.py", Line: 2, Parent: "C"},
+			{Name: "Inner", Path: "a.py", Line: 3, Parent: "C"},
+			{Name: "n", Path: "a.py", Line: 4, Parent: "C.Inner"},
+			{Name: "m", Path: "b.py", Line: 1, Parent: "C"},
+			{Name: "D", Path: "c.py", Line: 1},
+			{Name: "D", Path: "c.py", Line: 5},
+			{Name: "x", Path: "c.py", Line: 6, Parent: "D"},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	children := map[string][]string{}
+	for _, symbol := range symbols {
+		key := symbol.uri.Fragment + ":" + symbol.Name()
+		children[key] = append(children[key], symbolNames(symbol.Children())...)
+	}
+	want := map[string][]string{
+		"a.py:C":     {"m", "Inner"},
+		"a.py:m":     nil,
+		"a.py:Inner": {"n"},
+		"a.py:n":     nil,
+		"b.py:m":     nil,
+		"c.py:D":     nil, // ambiguous
+		"c.py:x":     nil,
+	}
+	if !reflect.DeepEqual(children, want) {
+		t.Errorf("got children %v, want %v", children, want)
+	}
+}
+
+func TestComputeSymbols_cancel(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	started := make(chan struct{})
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Second):
+			return nil, nil
+		}
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	start := time.Now()
+	_, _, err := computeSymbols(ctx, commit, "", &symbolsArgs{})
+	if err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("computeSymbols took %s after the context was cancelled", elapsed)
+	}
+
+	// A request that is already cancelled should not reach the backend at all.
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		t.Error("ListTags called with a cancelled context")
+		return nil, nil
+	}
+	if _, _, err := computeSymbols(ctx, commit, "", &symbolsArgs{}); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+type fakeHoverCodeIntelResolver struct {
+	CodeIntelResolver
+	hover       string
+	references  int
+	definitions []LocationResolver
+	calls       int
+}
+
+func (r *fakeHoverCodeIntelResolver) LSIF(ctx context.Context, args *LSIFQueryArgs) (LSIFQueryResolver, error) {
+	return r, nil
+}
+
+func (r *fakeHoverCodeIntelResolver) Definitions(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error) {
+	r.calls++
+	return fakeLocationConnectionResolver(r.definitions), nil
+}
+
+func (r *fakeHoverCodeIntelResolver) References(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error) {
+	r.calls++
+	n := r.references
+	if args.First != nil && int(*args.First) < n {
+		n = int(*args.First)
+	}
+	return fakeLocationConnectionResolver(make([]LocationResolver, n)), nil
+}
+
+type fakeLocationConnectionResolver []LocationResolver
+
+func (r fakeLocationConnectionResolver) Nodes(ctx context.Context) ([]LocationResolver, error) {
+	return r, nil
+}
+
+func (r fakeLocationConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	return graphqlutil.HasNextPage(false), nil
+}
+
+func (r *fakeHoverCodeIntelResolver) Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error) {
+	r.calls++
+	return fakeHoverResolver(r.hover), nil
+}
+
+type fakeHoverResolver string
+
+func (h fakeHoverResolver) Markdown() MarkdownResolver { return h }
+func (h fakeHoverResolver) Range() RangeResolver       { return nil }
+func (h fakeHoverResolver) Text() string               { return string(h) }
+func (h fakeHoverResolver) HTML() string               { return string(h) }
+
+func TestSymbolResolver_Documentation(t *testing.T) {
+	commit := &GitCommitResolver{repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}, oid: "c1"}
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	symbol := protocol.Symbol{Name: "Foo", Path: "a.go", Line: 3}
+
+	t.Run("no precise code intelligence", func(t *testing.T) {
+		doc, err := toSymbolResolver(symbol, baseURI, "go", commit).Documentation(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc != nil {
+			t.Errorf("got documentation %q, want nil", *doc)
+		}
+	})
+
+	t.Run("hover", func(t *testing.T) {
+		orig := EnterpriseResolvers.codeIntelResolver
+		defer func() { EnterpriseResolvers.codeIntelResolver = orig }()
+		fake := &fakeHoverCodeIntelResolver{hover: "Foo does things.\n"}
+		EnterpriseResolvers.codeIntelResolver = fake
+
+		resolver := toSymbolResolver(symbol, baseURI, "go", commit)
+		for i := 0; i < 2; i++ {
+			doc, err := resolver.Documentation(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if doc == nil || *doc != "Foo does things." {
+				t.Errorf("got documentation %v, want %q", doc, "Foo does things.")
+			}
+		}
+		if fake.calls != 1 {
+			t.Errorf("got %d hover requests, want 1", fake.calls)
+		}
+	})
+}
+
+func TestSymbolResolver_Definition(t *testing.T) {
+	commit := &GitCommitResolver{repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}, oid: "c1"}
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	symbol := protocol.Symbol{Name: "Foo", Path: "a.go", Line: 3}
+	precise := NewLocationResolver(
+		&GitTreeEntryResolver{commit: commit, stat: CreateFileInfo("a.go", false)},
+		&lsp.Range{Start: lsp.Position{Line: 3, Character: 5}, End: lsp.Position{Line: 3, Character: 8}},
+	)
+
+	tests := map[string]struct {
+		codeIntel   *fakeHoverCodeIntelResolver
+		wantPrecise bool
+	}{
+		"no precise code intelligence": {},
+		"no definition":                {codeIntel: &fakeHoverCodeIntelResolver{}},
+		"definition": {
+			codeIntel:   &fakeHoverCodeIntelResolver{definitions: []LocationResolver{precise}},
+			wantPrecise: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			orig := EnterpriseResolvers.codeIntelResolver
+			defer func() { EnterpriseResolvers.codeIntelResolver = orig }()
+			if test.codeIntel != nil {
+				EnterpriseResolvers.codeIntelResolver = test.codeIntel
+			}
+
+			resolver := toSymbolResolver(symbol, baseURI, "go", commit)
+			want := LocationResolver(resolver.Location())
+			if test.wantPrecise {
+				want = precise
+			}
+			for i := 0; i < 2; i++ {
+				got, err := resolver.Definition(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Errorf("got definition %v, want %v", got.Range(), want.Range())
+				}
+			}
+			if test.codeIntel != nil && test.codeIntel.calls != 1 {
+				t.Errorf("got %d definition requests, want 1", test.codeIntel.calls)
+			}
+		})
+	}
+}
+
+func TestComputeSymbols_pathPatterns(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotArgs search.SymbolsParameters
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotArgs = args
+		return []protocol.Symbol{
+			{Name: "a", Path: "src/api/a.go", Line: 1},
+			{Name: "b", Path: "src/api/b_test.go", Line: 1},
+			{Name: "c", Path: "src/web/c.go", Line: 1},
+			{Name: "d", Path: "vendor/src/api/d.go", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+
+	include := []string{`^src/api/`}
+	exclude := []string{`_test\.go$`, `^vendor/`}
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{IncludePatterns: &include, ExcludePatterns: &exclude})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(gotArgs.IncludePatterns, include) {
+		t.Errorf("got include patterns %v, want %v", gotArgs.IncludePatterns, include)
+	}
+	if want := `_test\.go$|^vendor/`; gotArgs.ExcludePattern != want {
+		t.Errorf("got exclude pattern %q, want %q", gotArgs.ExcludePattern, want)
+	}
+
+	invalid := []string{"("}
+	if _, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{ExcludePatterns: &invalid}); err == nil {
+		t.Error("got nil error for invalid exclude pattern")
+	}
+}
+
+func TestComputeSymbols_path(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotPath string
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotPath = args.Path
+		return []protocol.Symbol{{Name: "a", Path: "src/a.go", Line: 1}}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	for path, want := range map[string]string{"": "", "src/": "src", "/src/a.go": "src/a.go"} {
+		if _, _, err := computeSymbols(context.Background(), commit, path, &symbolsArgs{}); err != nil {
+			t.Fatal(err)
+		}
+		if gotPath != want {
+			t.Errorf("%q: got symbols service path %q, want %q", path, gotPath, want)
+		}
+	}
+}
+
+func TestComputeSymbols_paths(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotPaths []string
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotPaths = args.Paths
+		// Return symbols in other files, too, like older symbols services that ignore the paths.
+		return []protocol.Symbol{
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "b", Path: "b/b.go", Line: 1},
+			{Name: "c", Path: "c.go", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	paths := []string{"/c.go", "a.go", "c.go", "b"}
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Paths: &paths})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a.go", "b", "c.go"}; !reflect.DeepEqual(gotPaths, want) {
+		t.Errorf("got symbols service paths %v, want %v", gotPaths, want)
+	}
+	// Paths are files, so "b" doesn't match the symbols in the directory b.
+	if got, want := symbolNames(symbols), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for _, invalid := range [][]string{{"a.go", "/"}, make([]string, maxSymbolsPaths+1)} {
+		invalid := invalid
+		if _, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Paths: &invalid}); err == nil {
+			t.Errorf("got nil error for invalid paths (%d paths)", len(invalid))
+		}
+	}
+}
+
+func TestComputeSymbols_minQueryLength(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		SearchSymbolsMinQueryLength: &schema.SearchSymbolsMinQueryLength{SymbolsService: 2},
+	}})
+	defer conf.Mock(nil)
+
+	calls := 0
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		calls++
+		return []protocol.Symbol{{Name: "ab", Path: "a.go", Line: 1}}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		path, query string
+		wantCalls   int
+	}{
+		{path: "", query: " a ", wantCalls: 0},
+		{path: "", query: "ab", wantCalls: 1},
+		{path: "dir", query: "a", wantCalls: 1},
+	}
+	for _, test := range tests {
+		calls = 0
+		query := test.query
+		symbols, common, err := computeSymbols(context.Background(), commit, test.path, &symbolsArgs{Query: &query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != test.wantCalls {
+			t.Errorf("path %q, query %q: got %d symbols service calls, want %d", test.path, test.query, calls, test.wantCalls)
+		}
+		if test.wantCalls == 0 {
+			if len(symbols) != 0 {
+				t.Errorf("path %q, query %q: got %v, want no symbols", test.path, test.query, symbolNames(symbols))
+			}
+			if len(common.sourceErrors) != 1 || common.sourceErrors[0].source != symbolSourceSymbolsService {
+				t.Errorf("path %q, query %q: got source errors %v, want a symbols service error", test.path, test.query, common.sourceErrors)
+			}
+			if !common.unavailable {
+				t.Errorf("path %q, query %q: got available, want unavailable", test.path, test.query)
+			}
+		}
+	}
+}
+
+func TestComputeSymbols_exact(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotFirst []int
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotFirst = append(gotFirst, args.First)
+		return nil, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	first := int32(2)
+	for _, exact := range []bool{false, true} {
+		if _, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}, exact: exact}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The results with exactly first symbols are cached separately, because their limitHit is unknown.
+	if want := []int{3, 2}; !reflect.DeepEqual(gotFirst, want) {
+		t.Errorf("got symbols service limits %v, want %v", gotFirst, want)
+	}
+}
+
+func TestComputeSymbols_excludeGenerated(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "b", Path: "api/b.pb.go", Line: 1},
+			{Name: "c", Path: "web/node_modules/c.js", Line: 1},
+			{Name: "d", Path: "vendor/d.go", Line: 1},
+			{Name: "e", Path: "gen/e.go", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	exclude := true
+
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"a", "b", "e", "d", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("default: got %v, want %v", got, want)
+	}
+
+	symbols, _, err = computeSymbols(context.Background(), commit, "", &symbolsArgs{ExcludeGenerated: &exclude})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"a", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("excludeGenerated: got %v, want %v", got, want)
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchSymbolsGeneratedPatterns: []string{`^gen/`}}})
+	defer conf.Mock(nil)
+	symbols, _, err = computeSymbols(context.Background(), commit, "", &symbolsArgs{ExcludeGenerated: &exclude})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"a", "b", "d", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("site config patterns: got %v, want %v", got, want)
+	}
+}
+
+func TestComputeSymbols_noise(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchSymbolsNoisePatterns: map[string][]string{
+		"*": {`^_$`},
+		"c": {`^__anon`},
+	}}})
+	defer conf.Mock(nil)
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "", Path: "a.go", Line: 1, Language: "Go"},
+			{Name: "_", Path: "a.go", Line: 2, Language: "Go"},
+			{Name: "__anon1", Path: "a.go", Line: 3, Language: "Go"},
+			{Name: "f", Path: "a.go", Line: 4, Language: "Go"},
+			{Name: "__anon2", Path: "b.c", Line: 1, Language: "C"},
+			{Name: "g", Path: "b.c", Line: 2, Language: "C"},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"__anon1", "f", "g"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestComputeSymbols_languages(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "b", Path: "b.ts", Line: 1},
+			{Name: "c", Path: "c.py", Line: 1},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+
+	languages := []string{"go", "typescript"}
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Languages: &languages})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	unknown := []string{"notalanguage"}
+	if _, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{Languages: &unknown}); err == nil {
+		t.Error("got nil error for unknown language")
+	}
+}
+
+func TestSymbolConnectionResolver_Errors(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+
+	t.Run("partial results", func(t *testing.T) {
+		backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+			return []protocol.Symbol{{Name: "a", Path: "a.go", Line: 1}}, errors.New("ctags crashed")
+		}
+		conn, err := newSymbolConnectionResolver(context.Background(), commit, "", &symbolsArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes, _ := conn.Nodes(context.Background())
+		if got, want := symbolNames(nodes), []string{"a"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		errs := conn.Errors()
+		if len(errs) != 1 || errs[0].Source() != symbolSourceSymbolsService || errs[0].Message() != "ctags crashed" {
+			t.Errorf("got errors %v, want one symbols service error", errs)
+		}
+		if conn.Unavailable() {
+			t.Error("got unavailable, want available (with partial results)")
+		}
+	})
+
+	t.Run("symbols service not configured", func(t *testing.T) {
+		backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+			return nil, symbolsclient.ErrNotConfigured
+		}
+		conn, err := newSymbolConnectionResolver(context.Background(), commit, "", &symbolsArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nodes, _ := conn.Nodes(context.Background()); len(nodes) != 0 {
+			t.Errorf("got %d symbols, want none", len(nodes))
+		}
+		if errs := conn.Errors(); len(errs) != 1 || errs[0].Source() != symbolSourceSymbolsService {
+			t.Errorf("got errors %v, want one symbols service error", errs)
+		}
+		if !conn.Unavailable() {
+			t.Error("got available, want unavailable")
+		}
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+			return nil, errors.New("ctags crashed")
+		}
+		if _, err := newSymbolConnectionResolver(context.Background(), commit, "", &symbolsArgs{}); err == nil {
+			t.Error("got nil error, want the symbols service error")
+		}
+	})
+}
+
+func TestSymbolConnectionResolver_SourceTimings(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{{Name: "a", Path: "a.go", Line: 1}, {Name: "b", Path: "a.go", Line: 2}}, nil
+	}
+	siteAdmin := false
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: siteAdmin}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	timings := func() ([]string, error) {
+		conn, err := commit.Symbols(ctx, &symbolsArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		timings, err := conn.SourceTimings(ctx)
+		var got []string
+		for _, timing := range timings {
+			got = append(got, fmt.Sprintf("%s:%d", timing.Source(), timing.ResultCount()))
+		}
+		return got, err
+	}
+
+	if _, err := timings(); err != backend.ErrMustBeSiteAdmin {
+		t.Errorf("non-admin: got error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+	}
+
+	siteAdmin = true
+	// The symbols are cached by the first request.
+	if got, err := timings(); err != nil {
+		t.Fatal(err)
+	} else if want := []string{"cache:2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cached: got %v, want %v", got, want)
+	}
+
+	symbolsCacheMu.Lock()
+	symbolsCache.Clear()
+	symbolsCacheMu.Unlock()
+	if got, err := timings(); err != nil {
+		t.Fatal(err)
+	} else if want := []string{"symbols service:2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("uncached: got %v, want %v", got, want)
+	}
+}
+
+func TestSymbolResolver_Kind(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	tests := []struct {
+		ctagsKind string
+		kind      string
+		kindInt   int32
+	}{
+		{"function", "FUNCTION", 12},
+		{"class", "CLASS", 5},
+		{"", "UNKNOWN", 0},
+		{"notakind", "UNKNOWN", 0},
+	}
+	for _, test := range tests {
+		r := toSymbolResolver(protocol.Symbol{Name: "a", Path: "a.go", Kind: test.ctagsKind}, baseURI, "go", nil)
+		if got := r.Kind(); got != test.kind {
+			t.Errorf("%q: got kind %q, want %q", test.ctagsKind, got, test.kind)
+		}
+		if got := r.KindInt(); got != test.kindInt {
+			t.Errorf("%q: got kindInt %d, want %d", test.ctagsKind, got, test.kindInt)
+		}
+	}
+}
+
+func TestSymbolResolver_Kind_overrides(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		SearchSymbolsKindOverrides: map[string]map[string]string{
+			"go": {"Type": "STRUCT", "func": "notakind"},
+		},
+	}})
+	defer conf.Mock(nil)
+
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	tests := []struct {
+		language  string
+		ctagsKind string
+		kind      string
+	}{
+		{"go", "type", "STRUCT"},
+		{"typescript", "type", "CLASS"},
+		// Invalid overrides are ignored.
+		{"go", "func", "FUNCTION"},
+	}
+	for _, test := range tests {
+		r := toSymbolResolver(protocol.Symbol{Name: "a", Path: "a", Kind: test.ctagsKind}, baseURI, test.language, nil)
+		if got := r.Kind(); got != test.kind {
+			t.Errorf("%s %q: got kind %q, want %q", test.language, test.ctagsKind, got, test.kind)
+		}
+	}
+}
+
+func TestSymbolConnectionResolver_orderBy(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "zeta", Path: "a.go", Line: 1, Kind: "function"},
+			{Name: "Beta", Path: "a.go", Line: 2, Kind: "variable"},
+			{Name: "alpha", Path: "b.go", Line: 1, Kind: "class"},
+			{Name: "gamma", Path: "b.go", Line: 2, Kind: "function"},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	ctx := context.Background()
+	allPages := func(orderBy string, descending bool) [][]string {
+		t.Helper()
+		first := int32(2)
+		var pages [][]string
+		var after *string
+		for {
+			conn, err := commit.Symbols(ctx, &symbolsArgs{
+				ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+				OrderBy:        &orderBy,
+				Descending:     &descending,
+				After:          after,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			nodes, _ := conn.Nodes(ctx)
+			pages = append(pages, symbolNames(nodes))
+			pageInfo, _ := conn.PageInfo(ctx)
+			if !pageInfo.HasNextPage() {
+				return pages
+			}
+			after = pageInfo.EndCursor()
+		}
+	}
+
+	tests := []struct {
+		orderBy    string
+		descending bool
+		want       [][]string
+	}{
+		{"LOCATION", false, [][]string{{"zeta", "Beta"}, {"alpha", "gamma"}}},
+		{"NAME", false, [][]string{{"alpha", "Beta"}, {"gamma", "zeta"}}},
+		{"NAME", true, [][]string{{"zeta", "gamma"}, {"Beta", "alpha"}}},
+		{"KIND", false, [][]string{{"alpha", "gamma"}, {"zeta", "Beta"}}},
+	}
+	for _, test := range tests {
+		if got := allPages(test.orderBy, test.descending); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s (descending: %v): got pages %v, want %v", test.orderBy, test.descending, got, test.want)
+		}
+	}
+
+	invalid := "SIZE"
+	if _, err := commit.Symbols(ctx, &symbolsArgs{OrderBy: &invalid}); err == nil {
+		t.Error("got nil error for invalid order")
+	}
+}
+
+// TestComputeSymbols_orderUnsortedSource tests that orders other than by
+// location are applied to all of the symbols and not just to the first ones
+// that the symbols source returned.
+func TestComputeSymbols_orderUnsortedSource(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	backend.Mocks.Symbols.ListTags = unsortedListTags(
+		protocol.Symbol{Name: "c", Path: "a.go", Line: 1},
+		protocol.Symbol{Name: "b", Path: "a.go", Line: 2},
+		protocol.Symbol{Name: "a", Path: "a.go", Line: 3},
+	)
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		orderBy    string
+		descending bool
+		want       []string
+	}{
+		{orderBy: symbolOrderByName, want: []string{"a", "b"}},
+		{orderBy: symbolOrderByName, descending: true, want: []string{"c", "b"}},
+		{orderBy: symbolOrderByLocation, descending: true, want: []string{"a", "b"}},
+	}
+	for _, test := range tests {
+		first, orderBy, descending := int32(1), test.orderBy, test.descending
+		symbols, common, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{
+			ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+			OrderBy:        &orderBy,
+			Descending:     &descending,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := symbolNames(symbols); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s (descending %v): got %v, want %v", test.orderBy, test.descending, got, test.want)
+		}
+		if common.limitHit {
+			t.Errorf("%s (descending %v): got limitHit true, want false", test.orderBy, test.descending)
+		}
+	}
+}
+
+func TestSortSymbols_declarationOrder(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	input := []protocol.Symbol{
+		{Name: "x", Path: "a.go", Line: 1},
+		{Name: "x", Path: "a.go", Line: 2, Pattern: "/^var x, y int$/"},
+		{Name: "x", Path: "a.go", Line: 2, Pattern: "/^var y, x int$/"},
+		{Name: "X", Path: "a.go", Line: 3},
+		{Name: "y", Path: "a.go", Line: 4},
+	}
+	positions := func(symbols []*symbolResolver) []string {
+		var got []string
+		for _, symbol := range symbols {
+			got = append(got, fmt.Sprintf("%s@%d:%d", symbol.symbol.Name, symbol.Line(), symbol.Character()))
+		}
+		return got
+	}
+	tests := []struct {
+		order *symbolOrder
+		want  []string
+	}{
+		{&symbolOrder{by: symbolOrderByLocation}, []string{"x@0:0", "x@1:4", "x@1:7", "X@2:0", "y@3:0"}},
+		{&symbolOrder{by: symbolOrderByLocation, descending: true}, []string{"y@3:0", "X@2:0", "x@1:7", "x@1:4", "x@0:0"}},
+		// Symbols with the same name are in declaration order, even in descending order.
+		{&symbolOrder{by: symbolOrderByName}, []string{"x@0:0", "x@1:4", "x@1:7", "X@2:0", "y@3:0"}},
+		{&symbolOrder{by: symbolOrderByName, descending: true}, []string{"y@3:0", "x@0:0", "x@1:4", "x@1:7", "X@2:0"}},
+	}
+	rnd := rand.New(rand.NewSource(0))
+	for _, test := range tests {
+		for i := 0; i < 10; i++ {
+			symbols := make([]*symbolResolver, len(input))
+			for j, symbol := range input {
+				symbols[j] = toSymbolResolver(symbol, baseURI, "go", nil)
+			}
+			rnd.Shuffle(len(symbols), func(i, j int) { symbols[i], symbols[j] = symbols[j], symbols[i] })
+			sortSymbols(symbols, test.order)
+			if got := positions(symbols); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%+v: got %v, want %v", test.order, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSymbolResolver_Moniker(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	tests := []struct {
+		symbol protocol.Symbol
+		lang   string
+		want   string
+	}{
+		{protocol.Symbol{Name: "Serve", Parent: "Server", Path: "a.go"}, "go", "go:Server.Serve"},
+		{protocol.Symbol{Name: "main", Path: "a.go"}, "go", "go:main"},
+		{protocol.Symbol{Name: "x", Path: "a"}, "", "unknown:x"},
+	}
+	for _, test := range tests {
+		if got := toSymbolResolver(test.symbol, baseURI, test.lang, nil).Moniker(); got != test.want {
+			t.Errorf("got moniker %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestSymbolResolver_ID(t *testing.T) {
+	repo := &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}
+	inputRev := "master"
+	commit := &GitCommitResolver{repo: repo, oid: "c1", inputRev: &inputRev}
+	byRev, _ := gituri.Parse("git://repo?master")
+	byOID, _ := gituri.Parse("git://repo?c1")
+	symbol := protocol.Symbol{Name: "a", Path: "a.go", Line: 1}
+
+	id := toSymbolResolver(symbol, byOID, "go", commit).ID()
+	if got := toSymbolResolver(symbol, byRev, "go", commit).ID(); got != id {
+		t.Errorf("got ID %q for the input revision, want %q (the same as for the commit)", got, id)
+	}
+	for _, other := range []protocol.Symbol{
+		{Name: "b", Path: "a.go", Line: 1},
+		{Name: "a", Path: "b.go", Line: 1},
+		{Name: "a", Path: "a.go", Line: 2},
+	} {
+		if got := toSymbolResolver(other, byOID, "go", commit).ID(); got == id {
+			t.Errorf("%+v: got the same ID as %+v", other, symbol)
+		}
+	}
+	otherCommit := &GitCommitResolver{repo: repo, oid: "c2"}
+	if got := toSymbolResolver(symbol, byOID, "go", otherCommit).ID(); got == id {
+		t.Error("got the same ID at a different commit")
+	}
+}
+
+func TestSymbolAtPosition(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	symbol := func(name string, line, start, end int) *symbolResolver {
+		r := toSymbolResolver(protocol.Symbol{Name: name, Path: "a.go"}, baseURI, "go", nil)
+		r.location.lspRange = &lsp.Range{
+			Start: lsp.Position{Line: line, Character: start},
+			End:   lsp.Position{Line: line, Character: end},
+		}
+		return r
+	}
+	symbols := []*symbolResolver{
+		symbol("outer", 1, 0, 20),
+		symbol("inner", 1, 5, 10),
+		symbol("other", 3, 0, 5),
+	}
+	tests := []struct {
+		pos  lsp.Position
+		want string
+	}{
+		{lsp.Position{Line: 1, Character: 2}, "outer"},
+		{lsp.Position{Line: 1, Character: 5}, "inner"},
+		{lsp.Position{Line: 1, Character: 10}, "outer"},
+		{lsp.Position{Line: 3, Character: 4}, "other"},
+		{lsp.Position{Line: 3, Character: 5}, ""},
+		{lsp.Position{Line: 2, Character: 0}, ""},
+	}
+	for _, test := range tests {
+		got := ""
+		if s := symbolAtPosition(symbols, test.pos); s != nil {
+			got = s.Name()
+		}
+		if got != test.want {
+			t.Errorf("%+v: got %q, want %q", test.pos, got, test.want)
+		}
+	}
+}
+
+func TestSymbolConnectionResolver_maxPageSize(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return nil, nil
+	}
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	symbols := func(first int32) error {
+		_, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}})
+		return err
+	}
+
+	if err := symbols(1000); err != nil {
+		t.Errorf("first: 1000: got error %v, want nil", err)
+	}
+	if err := symbols(1001); err == nil {
+		t.Error("first: 1001: got nil error, want an error")
+	}
+	if err := symbols(-1); err == nil {
+		t.Error("first: -1: got nil error, want an error")
+	}
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{SearchSymbolsMaxPageSize: 2000}})
+	defer conf.Mock(nil)
+	if err := symbols(1001); err != nil {
+		t.Errorf("first: 1001 with search.symbols.maxPageSize 2000: got error %v, want nil", err)
+	}
+}
+
+// TestSymbolConnectionResolver_pageLargerThanSymbolsServiceLimit tests that a
+// page size allowed by search.symbols.maxPageSize but larger than the symbols
+// service's maximum (protocol.MaxFirst) returns a full page.
+func TestSymbolConnectionResolver_pageLargerThanSymbolsServiceLimit(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	backend.Mocks.Symbols.ListTags = mockPagedListTags(manySymbols(1234))
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	first := int32(800)
+	conn, err := commit.Symbols(context.Background(), &symbolsArgs{ConnectionArgs: graphqlutil.ConnectionArgs{First: &first}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes, _ := conn.Nodes(context.Background())
+	if len(nodes) != 800 {
+		t.Errorf("got %d symbols, want 800", len(nodes))
+	}
+	pageInfo, _ := conn.PageInfo(context.Background())
+	if !pageInfo.HasNextPage() {
+		t.Error("got hasNextPage false, want true")
+	}
+}
+
+func TestSymbolResolver_Detail(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	if detail := toSymbolResolver(protocol.Symbol{Name: "f", Path: "a.go", Signature: "(x int) error"}, baseURI, "go", nil).Detail(); detail == nil || *detail != "(x int) error" {
+		t.Errorf("got detail %v, want %q", detail, "(x int) error")
+	}
+	if detail := toSymbolResolver(protocol.Symbol{Name: "v", Path: "a.go"}, baseURI, "go", nil).Detail(); detail != nil {
+		t.Errorf("got detail %q, want nil", *detail)
+	}
+}
+
+func TestSymbolResolver_ReferenceCount(t *testing.T) {
+	commit := &GitCommitResolver{repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}}, oid: "c1"}
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	symbol := protocol.Symbol{Name: "Foo", Path: "a.go", Line: 3}
+
+	if count, err := toSymbolResolver(symbol, baseURI, "go", commit).ReferenceCount(context.Background()); err != nil || count != nil {
+		t.Errorf("without precise code intelligence: got %v, %v, want nil, nil", count, err)
+	}
+
+	orig := EnterpriseResolvers.codeIntelResolver
+	defer func() { EnterpriseResolvers.codeIntelResolver = orig }()
+	for _, test := range []struct{ references, want int }{{3, 3}, {500, symbolReferencesCountLimit}} {
+		fake := &fakeHoverCodeIntelResolver{references: test.references}
+		EnterpriseResolvers.codeIntelResolver = fake
+		resolver := toSymbolResolver(symbol, baseURI, "go", commit)
+		for i := 0; i < 2; i++ {
+			count, err := resolver.ReferenceCount(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count == nil || int(*count) != test.want {
+				t.Errorf("%d references: got count %v, want %d", test.references, count, test.want)
+			}
+		}
+		if fake.calls != 1 {
+			t.Errorf("got %d references requests, want 1", fake.calls)
+		}
+	}
+}
+
+func TestGitCommitResolver_Symbol(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	var gotQuery string
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		gotQuery = args.Query
+		return []protocol.Symbol{
+			{Name: "Close", Parent: "Reader", Path: "b.go", Line: 1},
+			{Name: "Close", Parent: "Writer", Path: "a.go", Line: 2},
+			{Name: "Close", Parent: "Reader", Path: "a.go", Line: 9},
+			{Name: "CloseAll", Path: "a.go", Line: 5},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	symbolAt := func(name string, containerName *string) string {
+		t.Helper()
+		symbol, err := commit.Symbol(context.Background(), &struct {
+			Name          string
+			ContainerName *string
+		}{Name: name, ContainerName: containerName})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if symbol == nil {
+			return ""
+		}
+		return fmt.Sprintf("%s.%s %s:%d", symbol.symbol.Parent, symbol.symbol.Name, symbol.symbol.Path, symbol.symbol.Line)
+	}
+
+	reader, none, other := "Reader", "", "Other"
+	if got, want := symbolAt("Close", nil), "Writer.Close a.go:2"; got != want {
+		t.Errorf("any container: got %q, want %q", got, want)
+	}
+	if want := `^Close$`; gotQuery != want {
+		t.Errorf("got backend query %q, want %q", gotQuery, want)
+	}
+	if got, want := symbolAt("Close", &reader), "Reader.Close a.go:9"; got != want {
+		t.Errorf("container: got %q, want %q", got, want)
+	}
+	if got, want := symbolAt("CloseAll", &none), ".CloseAll a.go:5"; got != want {
+		t.Errorf("no container: got %q, want %q", got, want)
+	}
+	if got := symbolAt("Close", &other); got != "" {
+		t.Errorf("missing: got %q, want no symbol", got)
+	}
+}
+
+func TestSymbolResolver_Package(t *testing.T) {
+	tests := []struct {
+		parent, parentKind string
+		want               string
+	}{
+		{parent: "graphqlbackend", parentKind: "package", want: "graphqlbackend"},
+		{parent: "std", parentKind: "namespace", want: "std"},
+		{parent: "Reader", parentKind: "struct", want: ""},
+		{parent: "", parentKind: "", want: ""},
+	}
+	for _, test := range tests {
+		r := &symbolResolver{symbol: protocol.Symbol{Name: "f", Parent: test.parent, ParentKind: test.parentKind}}
+		var got string
+		if p := r.Package(); p != nil {
+			got = *p
+		}
+		if got != test.want {
+			t.Errorf("%s %q: got %q, want %q", test.parentKind, test.parent, got, test.want)
+		}
+	}
+}
+
+func TestSymbolResolver_positions(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	r := toSymbolResolver(protocol.Symbol{Name: "foo", Path: "a.go", Line: 3, Pattern: "/^func foo() {$/"}, baseURI, "go", nil)
+	got := []int32{r.Line(), r.Character(), r.EndLine(), r.EndCharacter()}
+	if want := []int32{2, 5, 2, 8}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestGitCommitResolver_HasSymbols(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	calls := 0
+	var tags []protocol.Symbol
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		calls++
+		return tags, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	ctx := context.Background()
+	if has, err := commit.HasSymbols(ctx); err != nil || has {
+		t.Errorf("no symbols: got %v (error %v), want false", has, err)
+	}
+
+	symbolsCacheMu.Lock()
+	symbolsCache.Clear()
+	symbolsCacheMu.Unlock()
+	calls = 0
+	tags = []protocol.Symbol{{Name: "a", Path: "a.go", Line: 1}}
+	if has, err := commit.HasSymbols(ctx); err != nil || !has {
+		t.Errorf("symbols: got %v (error %v), want true", has, err)
+	}
+	if _, err := commit.Symbols(ctx, &symbolsArgs{}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("got %d symbols service calls, want the symbols list to be served from the cache", calls)
+	}
+}
+
+func TestSymbolConnectionResolver_Truncated(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		symbols := []protocol.Symbol{
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "b", Path: "a.go", Line: 2},
+			{Name: "c", Path: "a.go", Line: 3},
+		}
+		if len(symbols) > args.First {
+			symbols = symbols[:args.First]
+		}
+		return symbols, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	tests := []struct {
+		name  string
+		first int32
+		query string
+		want  bool
+	}{
+		{name: "all symbols fit", first: 3, want: false},
+		{name: "more symbols than first", first: 2, want: true},
+		// The page is taken from all of the symbols (up to symbolsWindowLimit), so filtering
+		// them below first means that there are no more.
+		{name: "filtered below first", first: 2, query: "^a$", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			first, query := test.first, test.query
+			conn, err := commit.Symbols(context.Background(), &symbolsArgs{
+				ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+				Query:          &query,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := conn.Truncated(); got != test.want {
+				t.Errorf("got truncated %v, want %v", got, test.want)
+			}
+		})
+	}
+}
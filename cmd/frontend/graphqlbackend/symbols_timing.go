@@ -0,0 +1,68 @@
+package graphqlbackend
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+)
+
+// symbolSourceCache is the name of the source of cached symbols, as reported
+// in symbol source timings.
+const symbolSourceCache = "cache"
+
+var symbolsSourceDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "src",
+	Subsystem: "graphql",
+	Name:      "symbols_source_duration_seconds",
+	Help:      "Time spent fetching the symbols of a repository, file, or directory from a symbols source.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"source", "status"})
+
+// symbolSourceTiming is how long a symbols source took to return symbols for a
+// request, and how many it returned.
+type symbolSourceTiming struct {
+	source      string
+	duration    time.Duration
+	resultCount int
+	err         error
+}
+
+// recordTiming records the timing of a call to a symbols source that started
+// at start.
+func (c *symbolsCommon) recordTiming(source string, start time.Time, resultCount int, err error) {
+	duration := time.Since(start)
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	symbolsSourceDuration.WithLabelValues(source, status).Observe(duration.Seconds())
+	c.timings = append(c.timings, &symbolSourceTiming{source: source, duration: duration, resultCount: resultCount, err: err})
+}
+
+func (t *symbolSourceTiming) Source() string { return t.source }
+
+func (t *symbolSourceTiming) DurationMilliseconds() int32 {
+	return int32(t.duration / time.Millisecond)
+}
+
+func (t *symbolSourceTiming) ResultCount() int32 { return int32(t.resultCount) }
+
+func (t *symbolSourceTiming) Error() *string {
+	if t.err == nil {
+		return nil
+	}
+	msg := t.err.Error()
+	return &msg
+}
+
+// SourceTimings returns how long each symbols source that was used to compute the symbols took.
+// Only site admins may view it, because it reveals details of the deployment.
+func (r *symbolConnectionResolver) SourceTimings(ctx context.Context) ([]*symbolSourceTiming, error) {
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return r.timings, nil
+}
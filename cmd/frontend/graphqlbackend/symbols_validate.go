@@ -0,0 +1,76 @@
+package graphqlbackend
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// maxSymbolsValidateDirs is the number of directories above which
+// filterSymbolsWithValidPaths lists the whole tree (whose listing is cached)
+// instead of listing each directory.
+const maxSymbolsValidateDirs = 20
+
+// filterSymbolsWithValidPaths returns the symbols whose files (or directories)
+// exist in the tree at the commit, omitting those that the symbols source
+// reported at paths that don't exist (e.g., because its index is stale).
+//
+// The tree is listed once per directory that contains symbols, or once in
+// total if there are many such directories.
+func filterSymbolsWithValidPaths(ctx context.Context, commit *GitCommitResolver, symbols []*symbolResolver) ([]*symbolResolver, error) {
+	dirs := map[string]struct{}{}
+	for _, symbol := range symbols {
+		dirs[symbolParentDir(symbol.uri.Fragment)] = struct{}{}
+	}
+
+	repo := gitserver.Repo{Name: commit.repo.repo.Name}
+	exists := map[string]bool{}
+	list := func(dir string, recurse bool) error {
+		entries, err := git.ReadDir(ctx, repo, api.CommitID(commit.oid), dir, recurse)
+		if os.IsNotExist(err) {
+			return nil // nothing in the directory exists
+		}
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			// Entry names are paths relative to the repository root.
+			exists[strings.Trim(entry.Name(), "/")] = true
+		}
+		return nil
+	}
+	if len(dirs) > maxSymbolsValidateDirs {
+		if err := list("", true); err != nil {
+			return nil, err
+		}
+	} else {
+		for dir := range dirs {
+			if err := list(dir, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if symbol.uri.Fragment == "" || exists[symbol.uri.Fragment] {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered, nil
+}
+
+// symbolParentDir returns the directory that contains the file or directory at
+// p ("" for the root).
+func symbolParentDir(p string) string {
+	dir := path.Dir(p)
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
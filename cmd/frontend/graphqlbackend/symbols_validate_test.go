@@ -0,0 +1,63 @@
+package graphqlbackend
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestComputeSymbols_validatePaths(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	defer git.ResetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "a", Path: "a.go", Line: 1},
+			{Name: "b", Path: "deleted.go", Line: 1},
+			{Name: "c", Path: "pkg/c.go", Line: 1},
+			{Name: "d", Path: "pkg/", Line: 1},
+			{Name: "e", Path: "gone/e.go", Line: 1},
+		}, nil
+	}
+	tree := map[string][]os.FileInfo{
+		"":    {CreateFileInfo("a.go", false), CreateFileInfo("pkg", true)},
+		"pkg": {CreateFileInfo("pkg/c.go", false)},
+	}
+	var listed []string
+	git.Mocks.ReadDir = func(commit api.CommitID, name string, recurse bool) ([]os.FileInfo, error) {
+		listed = append(listed, name)
+		entries, ok := tree[name]
+		if !ok {
+			return nil, &os.PathError{Op: "ls-tree", Path: name, Err: os.ErrNotExist}
+		}
+		return entries, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	yes := true
+	symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{ValidatePaths: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := symbolNames(symbols), []string{"a", "d", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// Each directory is listed once.
+	sort.Strings(listed)
+	if want := []string{"", "gone", "pkg"}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("got listed directories %q, want %q", listed, want)
+	}
+}
@@ -0,0 +1,67 @@
+package graphqlbackend
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The values of the GraphQL SymbolVisibility enum.
+const (
+	symbolVisibilityPublic  = "PUBLIC"
+	symbolVisibilityPrivate = "PRIVATE"
+	symbolVisibilityUnknown = "UNKNOWN"
+)
+
+// Visibility returns whether the symbol is visible outside of the file or
+// package (or other unit of code) it is defined in. Neither symbols source
+// reports access modifiers, so it is inferred from the language's naming
+// conventions: symbols are PUBLIC or PRIVATE only in languages whose
+// conventions determine it, and UNKNOWN otherwise.
+func (r *symbolResolver) Visibility() string /* enum SymbolVisibility */ {
+	if r.symbol.FileLimited {
+		// ctags reports symbols that are only visible in their file (e.g., static functions in C).
+		return symbolVisibilityPrivate
+	}
+	name := r.symbol.Name
+	switch r.language {
+	case "go":
+		// Exported identifiers start with an upper-case letter.
+		first, _ := utf8.DecodeRuneInString(name)
+		if unicode.IsUpper(first) {
+			return symbolVisibilityPublic
+		}
+		return symbolVisibilityPrivate
+	case "python":
+		// Names with a leading underscore are internal, except for special (dunder) names.
+		if strings.HasPrefix(name, "_") && !(strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__")) {
+			return symbolVisibilityPrivate
+		}
+		return symbolVisibilityPublic
+	case "dart":
+		// Identifiers that start with an underscore are private to their library.
+		if strings.HasPrefix(name, "_") {
+			return symbolVisibilityPrivate
+		}
+		return symbolVisibilityPublic
+	case "javascript", "typescript":
+		// Private class members start with "#" (or, by convention, an underscore). Whether
+		// other symbols are exported can't be told from their names.
+		if strings.HasPrefix(name, "#") || strings.HasPrefix(name, "_") {
+			return symbolVisibilityPrivate
+		}
+	}
+	return symbolVisibilityUnknown
+}
+
+// filterPrivateSymbols returns the symbols whose visibility is not PRIVATE (see
+// Visibility).
+func filterPrivateSymbols(symbols []*symbolResolver) []*symbolResolver {
+	filtered := symbols[:0]
+	for _, symbol := range symbols {
+		if symbol.Visibility() != symbolVisibilityPrivate {
+			filtered = append(filtered, symbol)
+		}
+	}
+	return filtered
+}
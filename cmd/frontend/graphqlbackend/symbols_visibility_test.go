@@ -0,0 +1,74 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+)
+
+func TestSymbolResolver_Visibility(t *testing.T) {
+	baseURI, _ := gituri.Parse("git://repo?c1")
+	tests := []struct {
+		symbol   protocol.Symbol
+		language string
+		want     string
+	}{
+		{protocol.Symbol{Name: "Serve"}, "go", "PUBLIC"},
+		{protocol.Symbol{Name: "serve"}, "go", "PRIVATE"},
+		{protocol.Symbol{Name: "_"}, "go", "PRIVATE"},
+		{protocol.Symbol{Name: "serve"}, "python", "PUBLIC"},
+		{protocol.Symbol{Name: "_serve"}, "python", "PRIVATE"},
+		{protocol.Symbol{Name: "__init__"}, "python", "PUBLIC"},
+		{protocol.Symbol{Name: "_serve"}, "dart", "PRIVATE"},
+		{protocol.Symbol{Name: "#count"}, "javascript", "PRIVATE"},
+		{protocol.Symbol{Name: "count"}, "typescript", "UNKNOWN"},
+		{protocol.Symbol{Name: "main"}, "java", "UNKNOWN"},
+		{protocol.Symbol{Name: "helper", FileLimited: true}, "c", "PRIVATE"},
+	}
+	for _, test := range tests {
+		test.symbol.Path = "a"
+		if got := toSymbolResolver(test.symbol, baseURI, test.language, nil).Visibility(); got != test.want {
+			t.Errorf("%s %q: got %s, want %s", test.language, test.symbol.Name, got, test.want)
+		}
+	}
+}
+
+func TestComputeSymbols_exportedOnly(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+
+	backend.Mocks.Symbols.ListTags = func(ctx context.Context, args search.SymbolsParameters) ([]protocol.Symbol, error) {
+		return []protocol.Symbol{
+			{Name: "Serve", Path: "a.go", Line: 1, Language: "Go"},
+			{Name: "serve", Path: "a.go", Line: 2, Language: "Go"},
+			{Name: "Main", Path: "b.java", Line: 1, Language: "Java"},
+		}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "repo"}},
+		oid:  "c1",
+	}
+	yes := true
+	for _, test := range []struct {
+		exportedOnly *bool
+		want         []string
+	}{
+		{nil, []string{"Serve", "serve", "Main"}},
+		{&yes, []string{"Serve", "Main"}},
+	} {
+		symbols, _, err := computeSymbols(context.Background(), commit, "", &symbolsArgs{ExportedOnly: test.exportedOnly})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := symbolNames(symbols); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("exportedOnly %v: got %v, want %v", test.exportedOnly, got, test.want)
+		}
+	}
+}
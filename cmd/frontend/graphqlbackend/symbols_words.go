@@ -0,0 +1,94 @@
+package graphqlbackend
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// symbolWordBoundariesSeparators is the value of the site config
+// "search.symbols.wordBoundaries" for languages whose symbol names are split
+// into words only at non-alphanumeric characters (not at camelCase humps).
+const symbolWordBoundariesSeparators = "separators"
+
+// symbolWordBoundaries is the compiled form of the site config
+// "search.symbols.wordBoundaries": the set of languages (in lowercase) whose
+// names are not split at camelCase humps.
+type symbolWordBoundaries map[string]bool
+
+func newSymbolWordBoundaries() symbolWordBoundaries {
+	b := symbolWordBoundaries{}
+	for language, boundaries := range conf.SearchSymbolsWordBoundaries() {
+		if boundaries == symbolWordBoundariesSeparators {
+			b[strings.ToLower(language)] = true
+		}
+	}
+	return b
+}
+
+// camelCase reports whether names in the language are split into words at
+// camelCase humps.
+func (b symbolWordBoundaries) camelCase(language string) bool {
+	return !b[language]
+}
+
+// containsWholeWords reports whether query occurs in name starting and ending
+// at word boundaries (see isWordBoundary), e.g., "get" in "getUser" and
+// "get_user" but not in "forget" or "getter".
+func containsWholeWords(name, query string, caseSensitive, camelCase bool) bool {
+	_, _, ok := indexWholeWords(name, query, caseSensitive, camelCase)
+	return ok
+}
+
+// indexWholeWords returns the byte offsets in name of the start and end of the
+// first occurrence of query that starts and ends at word boundaries (see
+// containsWholeWords).
+func indexWholeWords(name, query string, caseSensitive, camelCase bool) (start, end int, ok bool) {
+	n, q := []rune(name), []rune(query)
+	if len(q) == 0 {
+		return 0, 0, true
+	}
+	for i := 0; i+len(q) <= len(n); i++ {
+		if runesEqual(n[i:i+len(q)], q, caseSensitive) && isWordBoundary(n, i, camelCase) && isWordBoundary(n, i+len(q), camelCase) {
+			start = len(string(n[:i]))
+			return start, start + len(string(n[i:i+len(q)])), true
+		}
+	}
+	return 0, 0, false
+}
+
+func runesEqual(a, b []rune, caseSensitive bool) bool {
+	for i := range a {
+		if a[i] != b[i] && (caseSensitive || unicode.ToLower(a[i]) != unicode.ToLower(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordBoundary reports whether a word of name starts or ends before the
+// rune at index i. Words are separated by non-alphanumeric characters and, if
+// camelCase, by a lowercase letter or digit followed by an uppercase letter
+// (e.g., "get|User") and by the last of a run of uppercase letters that is
+// followed by a lowercase letter (e.g., "HTTP|Server").
+func isWordBoundary(name []rune, i int, camelCase bool) bool {
+	if i == 0 || i == len(name) {
+		return true
+	}
+	prev, cur := name[i-1], name[i]
+	if !isAlphanumeric(prev) || !isAlphanumeric(cur) {
+		return true
+	}
+	if !camelCase || !unicode.IsUpper(cur) {
+		return false
+	}
+	if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsUpper(prev) && i+1 < len(name) && unicode.IsLower(name[i+1])
+}
+
+func isAlphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
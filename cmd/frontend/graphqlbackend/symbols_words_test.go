@@ -0,0 +1,58 @@
+package graphqlbackend
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestContainsWholeWords(t *testing.T) {
+	tests := []struct {
+		name, query string
+		want        bool
+	}{
+		{"getUser", "get", true},
+		{"getUser", "user", true},
+		{"getUser", "getUser", true},
+		{"get_user", "get", true},
+		{"get_user", "user", true},
+		{"forget", "get", false},
+		{"getter", "get", false},
+		{"targetGet", "get", true},
+		{"HTTPServer", "http", true},
+		{"HTTPServer", "server", true},
+		{"HTTPServer", "TTP", false},
+		{"parseHTTP2Request", "http2", true},
+		{"a.b", "b", true},
+	}
+	for _, test := range tests {
+		if got := containsWholeWords(test.name, test.query, false, true); got != test.want {
+			t.Errorf("%q in %q: got %v, want %v", test.query, test.name, got, test.want)
+		}
+	}
+
+	if !containsWholeWords("getUser", "get", true, true) || containsWholeWords("getUser", "Get", true, true) {
+		t.Error("got case-insensitive match for a case-sensitive query")
+	}
+	if containsWholeWords("getUser", "get", false, false) {
+		t.Error("got match at a camelCase hump without camelCase word boundaries")
+	}
+}
+
+func TestNewSymbolWordBoundaries(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		SearchSymbolsWordBoundaries: map[string]string{"Lisp": "separators", "go": "camelCase"},
+	}})
+	defer conf.Mock(nil)
+
+	b := newSymbolWordBoundaries()
+	if b.camelCase("lisp") {
+		t.Error("lisp: got camelCase, want separators")
+	}
+	for _, language := range []string{"go", "java"} {
+		if !b.camelCase(language) {
+			t.Errorf("%s: got separators, want camelCase", language)
+		}
+	}
+}
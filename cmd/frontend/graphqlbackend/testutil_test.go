@@ -8,4 +8,11 @@ import (
 func resetMocks() {
 	db.Mocks = db.MockStores{}
 	backend.Mocks = backend.MockServices{}
+
+	symbolsCacheMu.Lock()
+	symbolsCache.Clear()
+	symbolsCacheMu.Unlock()
+	symbolsBlameCacheMu.Lock()
+	symbolsBlameCache.Clear()
+	symbolsBlameCacheMu.Unlock()
 }
@@ -9,15 +9,18 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 )
 
 type Entry struct {
 	Name       string
 	Path       string
 	Line       int
+	End        int // the last line of the definition (e.g., a function's body), or 0 if unknown
 	Kind       string
 	Language   string
 	Parent     string
@@ -68,7 +71,7 @@ func NewParser(ctagsCommand string) (Parser, error) {
 	// }
 
 	cmd := exec.Command(ctagsCommand, "--_interactive="+opt, "--fields=*",
-		"--languages=Basic,C,C#,C++,Clojure,Cobol,CSS,CUDA,D,Elixir,elm,Erlang,Go,GraphQL,Groovy,haskell,Java,JavaScript,Jsonnet,kotlin,Lisp,Lua,MatLab,ObjectiveC,OCaml,Pascal,Perl,Perl6,PHP,Protobuf,Python,R,Ruby,Rust,scala,Scheme,Sh,swift,SystemVerilog,Tcl,Thrift,typescript,tsx,Verilog,VHDL,Vim",
+		"--languages="+strings.Join(protocol.Languages, ","),
 		"--map-CSS=+.scss", "--map-CSS=+.less", "--map-CSS=+.sass",
 	)
 	in, err := cmd.StdinPipe()
@@ -225,6 +228,7 @@ func (p *ctagsProcess) Parse(name string, content []byte) (entries []Entry, err
 			Name:        rep.Name,
 			Path:        rep.Path,
 			Line:        rep.Line,
+			End:         rep.End,
 			Kind:        rep.Kind,
 			Language:    rep.Language,
 			Parent:      rep.Scope,
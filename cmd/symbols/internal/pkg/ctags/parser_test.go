@@ -55,6 +55,7 @@ class A implements B extends C {
 			Kind:     "class",
 			Language: "Java",
 			Line:     4,
+			End:      13,
 			Name:     "A",
 			Path:     "com/sourcegraph/A.java",
 		},
@@ -81,6 +82,7 @@ class A implements B extends C {
 			Kind:       "method",
 			Language:   "Java",
 			Line:       7,
+			End:        9,
 			Name:       "A",
 			Parent:     "A",
 			ParentKind: "class",
@@ -91,6 +93,7 @@ class A implements B extends C {
 			Kind:       "method",
 			Language:   "Java",
 			Line:       10,
+			End:        12,
 			Name:       "F",
 			Parent:     "A",
 			ParentKind: "class",
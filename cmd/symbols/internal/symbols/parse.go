@@ -37,7 +37,7 @@ func (s *Service) startParsers() error {
 	return nil
 }
 
-func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID api.CommitID, callback func(symbol protocol.Symbol) error) (err error) {
+func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID api.CommitID, maxFileSize int64, callback func(symbol protocol.Symbol) error) (err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "parseUncached")
 	defer func() {
 		if err != nil {
@@ -63,7 +63,7 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 	}()
 
 	tr.LazyPrintf("fetch")
-	parseRequests, errChan, err := s.fetchRepositoryArchive(ctx, repo, commitID)
+	parseRequests, errChan, err := s.fetchRepositoryArchive(ctx, repo, commitID, maxFileSize)
 	tr.LazyPrintf("fetch (returned chans)")
 	if err != nil {
 		return err
@@ -180,6 +180,7 @@ func entryToSymbol(e ctags.Entry) protocol.Symbol {
 		Name:        e.Name,
 		Path:        e.Path,
 		Line:        e.Line,
+		End:         e.End,
 		Kind:        e.Kind,
 		Language:    e.Language,
 		Parent:      e.Parent,
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/env"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 
 	"github.com/inconshreveable/log15"
 	"github.com/jmoiron/sqlx"
@@ -26,9 +27,6 @@ import (
 	nettrace "golang.org/x/net/trace"
 )
 
-// maxFileSize is the limit on file size in bytes. Only files smaller than this are processed.
-const maxFileSize = 1 << 19 // 512KB
-
 var libSqlite3Pcre = env.Get("LIBSQLITE3_PCRE", "", "path to the libsqlite3-pcre library")
 
 // MustRegisterSqlite3WithPcre registers a sqlite3 driver with PCRE support and
@@ -114,8 +112,9 @@ func (s *Service) search(ctx context.Context, args protocol.SearchArgs) (result
 // specified in `args`. If the database doesn't already exist in the disk cache,
 // it will create a new one and write all the symbols into it.
 func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (string, error) {
-	diskcacheFile, err := s.cache.OpenWithPath(ctx, fmt.Sprintf("%d-%s@%s", symbolsDBVersion, args.Repo, args.CommitID), func(fetcherCtx context.Context, tempDBFile string) error {
-		err := s.writeAllSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
+	maxFileSize := conf.SearchSymbolsMaxFileSizeBytes()
+	diskcacheFile, err := s.cache.OpenWithPath(ctx, symbolsDBKey(args, maxFileSize), func(fetcherCtx context.Context, tempDBFile string) error {
+		err := s.writeAllSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID, maxFileSize)
 		if err != nil {
 			if err == context.Canceled {
 				log15.Error("Unable to parse repository symbols within the context", "repo", args.Repo, "commit", args.CommitID, "query", args.Query)
@@ -132,6 +131,23 @@ func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (stri
 	return diskcacheFile.File.Name(), err
 }
 
+// defaultMaxFileSize is the default limit on file size in bytes (see
+// conf.SearchSymbolsMaxFileSizeBytes).
+const defaultMaxFileSize = 512 * 1024
+
+// symbolsDBKey returns the disk cache key of the sqlite3 database with the
+// symbols of the repo@commit specified in args, indexed with the given file
+// size limit.
+func symbolsDBKey(args protocol.SearchArgs, maxFileSize int64) string {
+	key := fmt.Sprintf("%d-%s@%s", symbolsDBVersion, args.Repo, args.CommitID)
+	if maxFileSize != defaultMaxFileSize {
+		// Databases indexed with the default limit keep their original key, so that
+		// they don't need to be reindexed.
+		key += fmt.Sprintf("-%d", maxFileSize)
+	}
+	return key
+}
+
 // isLiteralEquality checks if the given regex matches literal strings exactly.
 // Returns whether or not the regex is exact, along with the literal string if
 // so.
@@ -160,9 +176,8 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 		span.Finish()
 	}()
 
-	const maxFirst = 500
-	if args.First < 0 || args.First > maxFirst {
-		args.First = maxFirst
+	if args.First < 0 || args.First > protocol.MaxFirst {
+		args.First = protocol.MaxFirst
 	}
 
 	makeCondition := func(column string, regex string) []*sqlf.Query {
@@ -206,12 +221,35 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 		conditions = append(conditions, makeCondition("path", includePattern)...)
 	}
 	conditions = append(conditions, negateAll(makeCondition("path", args.ExcludePattern))...)
+	if args.Path != "" {
+		conditions = append(conditions, sqlf.Sprintf("(path = %s OR path GLOB %s)", args.Path, globEscape(args.Path)+"/*"))
+	}
+	if len(args.Paths) > 0 {
+		paths := make([]*sqlf.Query, len(args.Paths))
+		for i, path := range args.Paths {
+			paths[i] = sqlf.Sprintf("%s", path)
+		}
+		conditions = append(conditions, sqlf.Sprintf("path IN (%s)", sqlf.Join(paths, ",")))
+	}
+	if len(args.Kinds) > 0 {
+		kinds := make([]*sqlf.Query, len(args.Kinds))
+		for i, kind := range args.Kinds {
+			kinds[i] = sqlf.Sprintf("%s", strings.ToLower(kind))
+		}
+		conditions = append(conditions, sqlf.Sprintf("lower(kind) IN (%s)", sqlf.Join(kinds, ",")))
+	}
+
+	if args.Offset < 0 {
+		args.Offset = 0
+	}
 
 	var sqlQuery *sqlf.Query
 	if len(conditions) == 0 {
-		sqlQuery = sqlf.Sprintf("SELECT * FROM symbols LIMIT %s", args.First)
+		// Order by rowid (which is free for a full scan) so that paging through
+		// all of the symbols with Offset is stable.
+		sqlQuery = sqlf.Sprintf("SELECT * FROM symbols ORDER BY rowid LIMIT %s OFFSET %s", args.First, args.Offset)
 	} else {
-		sqlQuery = sqlf.Sprintf("SELECT * FROM symbols WHERE %s LIMIT %s", sqlf.Join(conditions, "AND"), args.First)
+		sqlQuery = sqlf.Sprintf("SELECT * FROM symbols WHERE %s LIMIT %s OFFSET %s", sqlf.Join(conditions, "AND"), args.First, args.Offset)
 	}
 
 	var symbolsInDB []symbolInDB
@@ -228,11 +266,28 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 	return res, nil
 }
 
+// globEscape escapes the metacharacters of SQLite's GLOB operator in s, so
+// that they match literally. Unlike LIKE, GLOB is case-sensitive, like paths.
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[':
+			b.WriteRune('[')
+			b.WriteRune(r)
+			b.WriteRune(']')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 // The version of the symbols database schema. This is included in the database
 // filenames to prevent a newer version of the symbols service from attempting
 // to read from a database created by an older (and likely incompatible) symbols
 // service. Increment this when you change the database schema.
-const symbolsDBVersion = 3
+const symbolsDBVersion = 4
 
 // symbolInDB is the same as `protocol.Symbol`, but with two additional columns:
 // namelowercase and pathlowercase, which enable indexed case insensitive
@@ -243,6 +298,7 @@ type symbolInDB struct {
 	Path          string
 	PathLowercase string // derived from `Path`
 	Line          int
+	EndLine       int // `End` (named differently because END is an SQL keyword)
 	Kind          string
 	Language      string
 	Parent        string
@@ -260,6 +316,7 @@ func symbolToSymbolInDB(symbol protocol.Symbol) symbolInDB {
 		Path:          symbol.Path,
 		PathLowercase: strings.ToLower(symbol.Path),
 		Line:          symbol.Line,
+		EndLine:       symbol.End,
 		Kind:          symbol.Kind,
 		Language:      symbol.Language,
 		Parent:        symbol.Parent,
@@ -276,6 +333,7 @@ func symbolInDBToSymbol(symbolInDB symbolInDB) protocol.Symbol {
 		Name:       symbolInDB.Name,
 		Path:       symbolInDB.Path,
 		Line:       symbolInDB.Line,
+		End:        symbolInDB.EndLine,
 		Kind:       symbolInDB.Kind,
 		Language:   symbolInDB.Language,
 		Parent:     symbolInDB.Parent,
@@ -289,7 +347,7 @@ func symbolInDBToSymbol(symbolInDB symbolInDB) protocol.Symbol {
 
 // writeAllSymbolsToNewDB fetches the repo@commit from gitserver, parses all the
 // symbols, and writes them to the blank database file `dbFile`.
-func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, repoName api.RepoName, commitID api.CommitID) error {
+func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, repoName api.RepoName, commitID api.CommitID, maxFileSize int64) error {
 	db, err := sqlx.Open("sqlite3_with_pcre", dbFile)
 	if err != nil {
 		return err
@@ -312,6 +370,7 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 			path VARCHAR(4096) NOT NULL,
 			pathlowercase VARCHAR(4096) NOT NULL,
 			line INT NOT NULL,
+			endline INT NOT NULL,
 			kind VARCHAR(255) NOT NULL,
 			language VARCHAR(255) NOT NULL,
 			parent VARCHAR(255) NOT NULL,
@@ -348,13 +407,13 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 	insertStatement, err := tx.PrepareNamed(
 		fmt.Sprintf(
 			"INSERT INTO symbols %s VALUES %s",
-			"( name,  namelowercase,  path,  pathlowercase,  line,  kind,  language,  parent,  parentkind,  signature,  pattern,  filelimited)",
-			"(:name, :namelowercase, :path, :pathlowercase, :line, :kind, :language, :parent, :parentkind, :signature, :pattern, :filelimited)"))
+			"( name,  namelowercase,  path,  pathlowercase,  line,  endline,  kind,  language,  parent,  parentkind,  signature,  pattern,  filelimited)",
+			"(:name, :namelowercase, :path, :pathlowercase, :line, :endline, :kind, :language, :parent, :parentkind, :signature, :pattern, :filelimited)"))
 	if err != nil {
 		return err
 	}
 
-	err = s.parseUncached(ctx, repoName, commitID, func(symbol protocol.Symbol) error {
+	err = s.parseUncached(ctx, repoName, commitID, maxFileSize, func(symbol protocol.Symbol) error {
 		symbolInDBValue := symbolToSymbolInDB(symbol)
 		_, err := insertStatement.Exec(&symbolInDBValue)
 		return err
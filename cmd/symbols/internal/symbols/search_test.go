@@ -54,7 +54,7 @@ func BenchmarkSearch(b *testing.B) {
 					b.Fatal(err)
 				}
 				defer os.Remove(tempFile.Name())
-				err = service.writeAllSymbolsToNewDB(ctx, tempFile.Name(), test.Repo, test.CommitID)
+				err = service.writeAllSymbolsToNewDB(ctx, tempFile.Name(), test.Repo, test.CommitID, defaultMaxFileSize)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -41,6 +41,18 @@ func init() {
 	}
 }
 
+func TestGlobEscape(t *testing.T) {
+	for input, want := range map[string]string{
+		"a/b.go":      "a/b.go",
+		"a/*.go":      "a/[*].go",
+		"a/[b]?/c.go": "a/[[]b][?]/c.go",
+	} {
+		if got := globEscape(input); got != want {
+			t.Errorf("globEscape(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
 func TestIsLiteralEquality(t *testing.T) {
 	type TestCase struct {
 		Regex       string
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf/confdefaults"
@@ -291,6 +292,168 @@ func SearchSymbolsParallelism() int {
 	return val
 }
 
+// SearchSymbolsTimeout returns 5s, or the site config "search.symbols.timeout"
+// value if configured and valid.
+func SearchSymbolsTimeout() time.Duration {
+	val := Get().SearchSymbolsTimeout
+	if val == "" {
+		return 5 * time.Second
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		return 5 * time.Second
+	}
+	return d
+}
+
+// SearchSymbolsMaxAttempts returns 3, or the site config
+// "search.symbols.maxAttempts" value if configured.
+func SearchSymbolsMaxAttempts() int {
+	val := Get().SearchSymbolsMaxAttempts
+	if val <= 0 {
+		return 3
+	}
+	return val
+}
+
+// SearchSymbolsRetryBackoff returns 100ms, or the site config
+// "search.symbols.retryBackoff" value if configured and valid.
+func SearchSymbolsRetryBackoff() time.Duration {
+	val := Get().SearchSymbolsRetryBackoff
+	if val == "" {
+		return 100 * time.Millisecond
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d < 0 {
+		return 100 * time.Millisecond
+	}
+	return d
+}
+
+// SearchSymbolsCacheSize returns 500, or the site config
+// "search.symbols.cacheSize" value if configured.
+func SearchSymbolsCacheSize() int {
+	val := Get().SearchSymbolsCacheSize
+	if val <= 0 {
+		return 500
+	}
+	return val
+}
+
+// SearchSymbolsMaxConcurrency returns 100, or the site config
+// "search.symbols.maxConcurrency" value if configured.
+func SearchSymbolsMaxConcurrency() int {
+	val := Get().SearchSymbolsMaxConcurrency
+	if val <= 0 {
+		return 100
+	}
+	return val
+}
+
+// SearchSymbolsMinQueryLength returns the site config
+// "search.symbols.minQueryLength" value (with zero minimums, which mean no
+// minimum, if not configured).
+func SearchSymbolsMinQueryLength() schema.SearchSymbolsMinQueryLength {
+	if val := Get().SearchSymbolsMinQueryLength; val != nil {
+		return *val
+	}
+	return schema.SearchSymbolsMinQueryLength{}
+}
+
+// SearchSymbolsMaxPageSize returns 1000, or the site config
+// "search.symbols.maxPageSize" value if configured.
+func SearchSymbolsMaxPageSize() int {
+	val := Get().SearchSymbolsMaxPageSize
+	if val <= 0 {
+		return 1000
+	}
+	return val
+}
+
+// SearchSymbolsMaxFileSizeBytes returns 512KB, or the site config
+// "search.symbols.maxFileSizeKB" value (in bytes) if configured.
+func SearchSymbolsMaxFileSizeBytes() int64 {
+	val := Get().SearchSymbolsMaxFileSizeKB
+	if val <= 0 {
+		return 512 * 1024
+	}
+	return int64(val) * 1024
+}
+
+// SearchSymbolsCacheTTL returns 1m, or the site config "search.symbols.cacheTTL"
+// value if configured and valid. A return value of 0 means that the cache is
+// disabled.
+func SearchSymbolsCacheTTL() time.Duration {
+	val := Get().SearchSymbolsCacheTTL
+	if val == "" {
+		return time.Minute
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return time.Minute
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// defaultSearchSymbolsGeneratedPatterns match the paths of commonly vendored
+// and generated files.
+var defaultSearchSymbolsGeneratedPatterns = []string{
+	`(^|/)vendor/`,
+	`(^|/)node_modules/`,
+	`(^|/)third_party/`,
+	`\.pb\.go$`,
+	`_generated\.[^/]+$`,
+	`\.min\.js$`,
+}
+
+// SearchSymbolsGeneratedPatterns returns the site config
+// "search.symbols.generatedPatterns" value, or a default list of patterns if
+// it is not set.
+func SearchSymbolsGeneratedPatterns() []string {
+	val := Get().SearchSymbolsGeneratedPatterns
+	if val == nil {
+		return defaultSearchSymbolsGeneratedPatterns
+	}
+	return val
+}
+
+// defaultSearchSymbolsReferenceKinds are the ctags kinds of symbols that name
+// references rather than definitions (packageName is the kind of the names of
+// imported Go packages).
+var defaultSearchSymbolsReferenceKinds = []string{"packageName"}
+
+// SearchSymbolsReferenceKinds returns the site config
+// "search.symbols.referenceKinds" value, or the default kinds if not
+// configured.
+func SearchSymbolsReferenceKinds() []string {
+	val := Get().SearchSymbolsReferenceKinds
+	if val == nil {
+		return defaultSearchSymbolsReferenceKinds
+	}
+	return val
+}
+
+// SearchSymbolsKindOverrides returns the site config
+// "search.symbols.kindOverrides" value (nil if not configured).
+func SearchSymbolsKindOverrides() map[string]map[string]string {
+	return Get().SearchSymbolsKindOverrides
+}
+
+// SearchSymbolsWordBoundaries returns the site config
+// "search.symbols.wordBoundaries" value (nil if not configured).
+func SearchSymbolsWordBoundaries() map[string]string {
+	return Get().SearchSymbolsWordBoundaries
+}
+
+// SearchSymbolsNoisePatterns returns the site config
+// "search.symbols.noisePatterns" value (nil if not configured).
+func SearchSymbolsNoisePatterns() map[string][]string {
+	return Get().SearchSymbolsNoisePatterns
+}
+
 func PermissionsBackgroundSyncEnabled() bool {
 	val := Get().PermissionsBackgroundSync
 	if val == nil {
@@ -64,6 +64,25 @@ type SymbolsParameters struct {
 	// need to match to get included in the result
 	ExcludePattern string
 
+	// Path is an optional file or directory path (relative to the repository
+	// root, without leading or trailing slashes). If set, only the symbols in
+	// the file at Path or in files beneath the directory at Path are returned.
+	Path string
+
+	// Paths is an optional list of file paths (relative to the repository
+	// root, without leading or trailing slashes). If set, only the symbols in
+	// these files are returned.
+	Paths []string
+
+	// Kinds is an optional list of ctags kinds (case-insensitive). If set,
+	// only the symbols of these kinds are returned. Older symbols services
+	// ignore it, so callers must not rely on it to filter the symbols.
+	Kinds []string
+
+	// Offset is the number of symbols to skip before the first one returned,
+	// for paging through the symbols in the order they are stored in.
+	Offset int
+
 	// First indicates that only the first n symbols should be returned.
 	First int
 }
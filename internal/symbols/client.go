@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -42,6 +43,10 @@ var DefaultClient = &Client{
 	HTTPLimiter: parallel.NewRun(500),
 }
 
+// ErrNotConfigured is returned by the client's methods when no symbols service
+// URL has been configured.
+var ErrNotConfigured = errors.New("a symbols service has not been configured")
+
 // Client is a symbols service client.
 type Client struct {
 	// URL to symbols service.
@@ -65,7 +70,7 @@ type key struct {
 func (c *Client) url(key key) (string, error) {
 	c.once.Do(func() {
 		if len(strings.Fields(c.URL)) == 0 {
-			c.endpoint = endpoint.Empty(errors.New("a symbols service has not been configured"))
+			c.endpoint = endpoint.Empty(ErrNotConfigured)
 		} else {
 			c.endpoint = endpoint.New(c.URL)
 		}
@@ -95,7 +100,7 @@ func (c *Client) Search(ctx context.Context, args search.SymbolsParameters) (res
 	if resp.StatusCode != http.StatusOK {
 		// best-effort inclusion of body in error message
 		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
-		return nil, errors.Errorf("Symbol.Search http status %d for %+v: %s", resp.StatusCode, resp.StatusCode, string(body))
+		return nil, &statusError{code: resp.StatusCode, body: string(body)}
 	}
 
 	err = json.NewDecoder(resp.Body).Decode(&result)
@@ -150,3 +155,24 @@ func (c *Client) httpPost(ctx context.Context, method string, key key, payload i
 
 	return ctxhttp.Do(ctx, c.HTTPClient, req)
 }
+
+// statusError is returned when the symbols service responds with an HTTP
+// error status.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("Symbol.Search http status %d for %+v: %s", e.code, e.code, e.body)
+}
+
+// Temporary reports whether the request may succeed if retried, because the
+// symbols service (or a proxy in front of it) is overloaded or restarting.
+func (e *statusError) Temporary() bool {
+	switch e.code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,12 @@
+package protocol
+
+// Languages are the names of the ctags parsers that the symbols service uses
+// (as accepted by ctags's --languages flag), so only files in these languages
+// have symbols.
+var Languages = []string{
+	"Basic", "C", "C#", "C++", "Clojure", "Cobol", "CSS", "CUDA", "D", "Elixir", "elm", "Erlang", "Go",
+	"GraphQL", "Groovy", "haskell", "Java", "JavaScript", "Jsonnet", "kotlin", "Lisp", "Lua", "MatLab",
+	"ObjectiveC", "OCaml", "Pascal", "Perl", "Perl6", "PHP", "Protobuf", "Python", "R", "Ruby", "Rust",
+	"scala", "Scheme", "Sh", "swift", "SystemVerilog", "Tcl", "Thrift", "typescript", "tsx", "Verilog",
+	"VHDL", "Vim",
+}
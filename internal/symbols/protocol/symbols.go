@@ -33,10 +33,35 @@ type SearchArgs struct {
 	// need to match to get included in the result
 	ExcludePattern string
 
-	// First indicates that only the first n symbols should be returned.
+	// Path is an optional file or directory path (relative to the repository
+	// root, without leading or trailing slashes). If set, only the symbols in
+	// the file at Path or in files beneath the directory at Path are returned.
+	Path string
+
+	// Paths is an optional list of file paths (relative to the repository
+	// root, without leading or trailing slashes). If set, only the symbols in
+	// these files are returned.
+	Paths []string
+
+	// Kinds is an optional list of ctags kinds (case-insensitive). If set,
+	// only the symbols of these kinds are returned. Older symbols services
+	// ignore it, so callers must not rely on it to filter the symbols.
+	Kinds []string
+
+	// Offset is the number of symbols to skip before the first one returned,
+	// for paging through the symbols in the order they are stored in.
+	Offset int
+
+	// First indicates that only the first n symbols should be returned. At
+	// most MaxFirst symbols are returned, however large it is.
 	First int
 }
 
+// MaxFirst is the maximum number of symbols that the symbols service returns
+// for a search. Callers that need more symbols must page through them with
+// SearchArgs.Offset.
+const MaxFirst = 500
+
 // SearchResult is the result of a search on the symbols service.
 type SearchResult struct {
 	Symbols []Symbol // code symbols
@@ -47,6 +72,7 @@ type Symbol struct {
 	Name       string
 	Path       string
 	Line       int
+	End        int // the last line of the definition, or 0 if unknown
 	Kind       string
 	Language   string
 	Parent     string
@@ -21,6 +21,8 @@ var Mocks, emptyMocks struct {
 	ResolveRevision  func(spec string, opt *ResolveRevisionOptions) (api.CommitID, error)
 	Stat             func(commit api.CommitID, name string) (os.FileInfo, error)
 	GetObject        func(objectName string) (OID, ObjectType, error)
+	BlameFile        func(path string, opt *BlameOptions) ([]*Hunk, error)
+	MergeBase        func(a, b api.CommitID) (api.CommitID, error)
 }
 
 // ResetMocks clears the mock functions set on Mocks (so that subsequent tests don't inadvertently
@@ -869,6 +869,14 @@ type SearchScope struct {
 	Value string `json:"value"`
 }
 
+// SearchSymbolsMinQueryLength description: The minimum length (in characters) of the query for listing the symbols of a whole repository with each symbols source. Shorter queries (including empty ones) match almost every symbol, which is expensive for some sources and rarely useful (e.g., for autocompletion). If the query is too short for indexed search, the symbols service is used instead; if it is too short for the symbols service, no symbols are returned, and the connection's errors say why. Listing the symbols of a file or directory is not limited. Defaults to 0 (no minimum) for both sources.
+type SearchSymbolsMinQueryLength struct {
+	// IndexedSearch description: The minimum query length for indexed search.
+	IndexedSearch int `json:"indexedSearch,omitempty"`
+	// SymbolsService description: The minimum query length for the symbols service.
+	SymbolsService int `json:"symbolsService,omitempty"`
+}
+
 // Sentry description: Configuration for Sentry
 type Sentry struct {
 	// Dsn description: Sentry Data Source Name (DSN). Per the Sentry docs (https://docs.sentry.io/quickstart/#about-the-dsn), it should match the following pattern: '{PROTOCOL}://{PUBLIC_KEY}@{HOST}/{PATH}{PROJECT_ID}'.
@@ -1033,6 +1041,34 @@ type SiteConfiguration struct {
 	SearchIndexSymbolsEnabled *bool `json:"search.index.symbols.enabled,omitempty"`
 	// SearchLargeFiles description: A list of file glob patterns where matching files will be indexed and searched regardless of their size. The glob pattern syntax can be found here: https://golang.org/pkg/path/filepath/#Match.
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
+	// SearchSymbolsCacheSize description: The maximum number of symbol lists (each for a repository, file, or directory at a commit, with a set of filters) that are cached in memory (see search.symbols.cacheTTL). Because cached lists are keyed by commit, lists for older revisions stop being used when a repository's index moves to a new commit, and are evicted first. Defaults to 500.
+	SearchSymbolsCacheSize int `json:"search.symbols.cacheSize,omitempty"`
+	// SearchSymbolsCacheTTL description: How long the symbols of a repository, file, or directory (for a given commit and set of filters) are cached in memory after they are computed. The string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). Set to "0s" to disable the cache. Defaults to "1m".
+	SearchSymbolsCacheTTL string `json:"search.symbols.cacheTTL,omitempty"`
+	// SearchSymbolsGeneratedPatterns description: A list of regular expressions matching the paths of vendored and generated files, whose symbols are omitted from symbol lists when the excludeGenerated argument is true. Defaults to patterns matching common vendor directories (vendor/, node_modules/, third_party/) and generated files (*.pb.go, *_generated.*, *.min.js).
+	SearchSymbolsGeneratedPatterns []string `json:"search.symbols.generatedPatterns,omitempty"`
+	// SearchSymbolsKindOverrides description: Overrides of the symbol kind (e.g., CLASS or INTERFACE) that symbols of a ctags kind are reported as, keyed by language (in lowercase, as in Symbol.language) and then by ctags kind (case-insensitive). By default, ctags kinds are mapped to the closest symbol kind regardless of language.
+	SearchSymbolsKindOverrides map[string]map[string]string `json:"search.symbols.kindOverrides,omitempty"`
+	// SearchSymbolsMaxAttempts description: The maximum number of times that the symbols service is called for a single symbols request, when earlier calls fail with a temporary error (e.g., HTTP 503 while the symbols service is restarting). Retries must finish within search.symbols.timeout. Set to 1 to disable retries. Defaults to 3.
+	SearchSymbolsMaxAttempts int `json:"search.symbols.maxAttempts,omitempty"`
+	// SearchSymbolsMaxConcurrency description: The maximum number of symbol lists that the frontend computes at the same time (across all users). Symbols requests beyond this limit fail immediately with an error asking the client to try again, instead of using more frontend memory. Cached symbol lists are not counted. Defaults to 100.
+	SearchSymbolsMaxConcurrency int `json:"search.symbols.maxConcurrency,omitempty"`
+	// SearchSymbolsMaxFileSizeKB description: The maximum size (in kilobytes) of files whose symbols are listed by the symbols service. Larger files (which are often minified or generated) are skipped, as are binary files. Changes apply to commits whose symbols are indexed after the change. Defaults to 512.
+	SearchSymbolsMaxFileSizeKB int `json:"search.symbols.maxFileSizeKB,omitempty"`
+	// SearchSymbolsMaxPageSize description: The maximum number of symbols that can be requested at a time (with the first argument) when listing the symbols of a repository, file, or directory. Requests for more symbols fail. Defaults to 1000.
+	SearchSymbolsMaxPageSize int `json:"search.symbols.maxPageSize,omitempty"`
+	// SearchSymbolsMinQueryLength description: The minimum length (in characters) of the query for listing the symbols of a whole repository with each symbols source. Shorter queries (including empty ones) match almost every symbol, which is expensive for some sources and rarely useful (e.g., for autocompletion). If the query is too short for indexed search, the symbols service is used instead; if it is too short for the symbols service, no symbols are returned, and the connection's errors say why. Listing the symbols of a file or directory is not limited. Defaults to 0 (no minimum) for both sources.
+	SearchSymbolsMinQueryLength *SearchSymbolsMinQueryLength `json:"search.symbols.minQueryLength,omitempty"`
+	// SearchSymbolsNoisePatterns description: Regular expressions matching the names of noisy symbols (e.g., compiler-generated or anonymous symbols) to omit from symbol lists, keyed by language (in lowercase, as in Symbol.language). The patterns for the key "*" apply to all languages. Symbols with empty names are always omitted.
+	SearchSymbolsNoisePatterns map[string][]string `json:"search.symbols.noisePatterns,omitempty"`
+	// SearchSymbolsReferenceKinds description: The ctags kinds (case-insensitive) of symbols that name references to definitions elsewhere (such as imported package names) rather than definitions. Symbols of these kinds are omitted from symbol lists unless the definitionsOnly argument is false. Defaults to ["packageName"] (the names of imported Go packages).
+	SearchSymbolsReferenceKinds []string `json:"search.symbols.referenceKinds,omitempty"`
+	// SearchSymbolsRetryBackoff description: How long to wait before retrying a call to the symbols service that failed with a temporary error (see search.symbols.maxAttempts). The wait doubles with each retry. Defaults to "100ms".
+	SearchSymbolsRetryBackoff string `json:"search.symbols.retryBackoff,omitempty"`
+	// SearchSymbolsTimeout description: The maximum time to wait for each symbols source (indexed search or the symbols service) when listing the symbols of a repository, file, or directory. The string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). Defaults to "5s".
+	SearchSymbolsTimeout string `json:"search.symbols.timeout,omitempty"`
+	// SearchSymbolsWordBoundaries description: How symbol names are split into words for the WHOLE_WORD symbol match mode, keyed by language (in lowercase, as in Symbol.language). With "camelCase" (the default), words are separated by non-alphanumeric characters (such as "_" in snake_case) and by changes from lowercase to uppercase. With "separators", only non-alphanumeric characters separate words, for languages whose names are not conventionally camelCase (or are case-insensitive).
+	SearchSymbolsWordBoundaries map[string]string `json:"search.symbols.wordBoundaries,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
 	UpdateChannel string `json:"update.channel,omitempty"`
 	// UseJaeger description: DEPRECATED. Use `"observability.tracing": { "sampling": "all" }`, instead. Enables Jaeger tracing.
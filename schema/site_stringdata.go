@@ -43,6 +43,134 @@ const SiteSchemaJSON = `{
       "group": "Search",
       "examples": [["go.sum", "package-lock.json", "*.thrift"]]
     },
+    "search.symbols.generatedPatterns": {
+      "description": "A list of regular expressions matching the paths of vendored and generated files, whose symbols are omitted from symbol lists when the excludeGenerated argument is true. Defaults to patterns matching common vendor directories (vendor/, node_modules/, third_party/) and generated files (*.pb.go, *_generated.*, *.min.js).",
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "group": "Search",
+      "examples": [["(^|/)vendor/", "\\.pb\\.go$", "(^|/)gen/"]]
+    },
+    "search.symbols.kindOverrides": {
+      "description": "Overrides of the symbol kind (e.g., CLASS or INTERFACE) that symbols of a ctags kind are reported as, keyed by language (in lowercase, as in Symbol.language) and then by ctags kind (case-insensitive). By default, ctags kinds are mapped to the closest symbol kind regardless of language.",
+      "type": "object",
+      "additionalProperties": {
+        "type": "object",
+        "additionalProperties": {
+          "type": "string",
+          "enum": ["FILE", "MODULE", "NAMESPACE", "PACKAGE", "CLASS", "METHOD", "PROPERTY", "FIELD", "CONSTRUCTOR", "ENUM", "INTERFACE", "FUNCTION", "VARIABLE", "CONSTANT", "STRING", "NUMBER", "BOOLEAN", "ARRAY", "OBJECT", "KEY", "NULL", "ENUMMEMBER", "STRUCT", "EVENT", "OPERATOR", "TYPEPARAMETER"]
+        }
+      },
+      "group": "Search",
+      "examples": [{ "go": { "type": "STRUCT" }, "c": { "typedef": "TYPEPARAMETER" } }]
+    },
+    "search.symbols.wordBoundaries": {
+      "description": "How symbol names are split into words for the WHOLE_WORD symbol match mode, keyed by language (in lowercase, as in Symbol.language). With \"camelCase\" (the default), words are separated by non-alphanumeric characters (such as \"_\" in snake_case) and by changes from lowercase to uppercase. With \"separators\", only non-alphanumeric characters separate words, for languages whose names are not conventionally camelCase (or are case-insensitive).",
+      "type": "object",
+      "additionalProperties": {
+        "type": "string",
+        "enum": ["camelCase", "separators"]
+      },
+      "group": "Search",
+      "examples": [{ "lisp": "separators", "sql": "separators" }]
+    },
+    "search.symbols.referenceKinds": {
+      "description": "The ctags kinds (case-insensitive) of symbols that name references to definitions elsewhere (such as imported package names) rather than definitions. Symbols of these kinds are omitted from symbol lists unless the definitionsOnly argument is false. Defaults to [\"packageName\"] (the names of imported Go packages).",
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "group": "Search",
+      "examples": [["packageName", "import"]]
+    },
+    "search.symbols.noisePatterns": {
+      "description": "Regular expressions matching the names of noisy symbols (e.g., compiler-generated or anonymous symbols) to omit from symbol lists, keyed by language (in lowercase, as in Symbol.language). The patterns for the key \"*\" apply to all languages. Symbols with empty names are always omitted.",
+      "type": "object",
+      "additionalProperties": {
+        "type": "array",
+        "items": {
+          "type": "string",
+          "format": "regex"
+        }
+      },
+      "group": "Search",
+      "examples": [{ "*": ["^_$"], "c": ["^__anon"], "go": ["^init\\.\\d+$"] }]
+    },
+    "search.symbols.cacheTTL": {
+      "description": "How long the symbols of a repository, file, or directory (for a given commit and set of filters) are cached in memory after they are computed. The string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). Set to \"0s\" to disable the cache. Defaults to \"1m\".",
+      "type": "string",
+      "group": "Search",
+      "examples": ["5m"]
+    },
+    "search.symbols.cacheSize": {
+      "description": "The maximum number of symbol lists (each for a repository, file, or directory at a commit, with a set of filters) that are cached in memory (see search.symbols.cacheTTL). Because cached lists are keyed by commit, lists for older revisions stop being used when a repository's index moves to a new commit, and are evicted first. Defaults to 500.",
+      "type": "integer",
+      "minimum": 1,
+      "group": "Search",
+      "examples": [2000]
+    },
+    "search.symbols.maxFileSizeKB": {
+      "description": "The maximum size (in kilobytes) of files whose symbols are listed by the symbols service. Larger files (which are often minified or generated) are skipped, as are binary files. Changes apply to commits whose symbols are indexed after the change. Defaults to 512.",
+      "type": "integer",
+      "minimum": 1,
+      "group": "Search",
+      "examples": [1024]
+    },
+    "search.symbols.maxAttempts": {
+      "description": "The maximum number of times that the symbols service is called for a single symbols request, when earlier calls fail with a temporary error (e.g., HTTP 503 while the symbols service is restarting). Retries must finish within search.symbols.timeout. Set to 1 to disable retries. Defaults to 3.",
+      "type": "integer",
+      "minimum": 1,
+      "maximum": 10,
+      "group": "Search",
+      "examples": [1]
+    },
+    "search.symbols.retryBackoff": {
+      "description": "How long to wait before retrying a call to the symbols service that failed with a temporary error (see search.symbols.maxAttempts). The wait doubles with each retry. Defaults to \"100ms\".",
+      "type": "string",
+      "group": "Search",
+      "examples": ["500ms"]
+    },
+    "search.symbols.minQueryLength": {
+      "description": "The minimum length (in characters) of the query for listing the symbols of a whole repository with each symbols source. Shorter queries (including empty ones) match almost every symbol, which is expensive for some sources and rarely useful (e.g., for autocompletion). If the query is too short for indexed search, the symbols service is used instead; if it is too short for the symbols service, no symbols are returned, and the connection's errors say why. Listing the symbols of a file or directory is not limited. Defaults to 0 (no minimum) for both sources.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "indexedSearch": {
+          "description": "The minimum query length for indexed search.",
+          "type": "integer",
+          "minimum": 0
+        },
+        "symbolsService": {
+          "description": "The minimum query length for the symbols service.",
+          "type": "integer",
+          "minimum": 0
+        }
+      },
+      "group": "Search",
+      "examples": [{ "symbolsService": 2 }]
+    },
+    "search.symbols.maxConcurrency": {
+      "description": "The maximum number of symbol lists that the frontend computes at the same time (across all users). Symbols requests beyond this limit fail immediately with an error asking the client to try again, instead of using more frontend memory. Cached symbol lists are not counted. Defaults to 100.",
+      "type": "integer",
+      "minimum": 1,
+      "group": "Search",
+      "examples": [200]
+    },
+    "search.symbols.maxPageSize": {
+      "description": "The maximum number of symbols that can be requested at a time (with the first argument) when listing the symbols of a repository, file, or directory. Requests for more symbols fail. Defaults to 1000.",
+      "type": "integer",
+      "minimum": 1,
+      "maximum": 10000,
+      "group": "Search",
+      "examples": [5000]
+    },
+    "search.symbols.timeout": {
+      "description": "The maximum time to wait for each symbols source (indexed search or the symbols service) when listing the symbols of a repository, file, or directory. The string format is that of the Duration type in the Go time package (https://golang.org/pkg/time/#ParseDuration). Defaults to \"5s\".",
+      "type": "string",
+      "group": "Search",
+      "examples": ["10s"]
+    },
     "debug.search.symbolsParallelism": {
       "description": "(debug) controls the amount of symbol search parallelism. Defaults to 20. It is not recommended to change this outside of debugging scenarios. This option will be removed in a future version.",
       "type": "integer",